@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPageToken(t *testing.T) {
+	page, err := decodePageToken(encodePageToken(3))
+	if err != nil || page != 3 {
+		t.Errorf("Expected page 3, got: %d, %v", page, err)
+	}
+
+	page, err = decodePageToken("")
+	if err != nil || page != 1 {
+		t.Errorf("Expected empty token to be first page, got: %d, %v", page, err)
+	}
+
+	for _, token := range []string{"!!", encodePageToken(0), "YWJj"} {
+		if _, err := decodePageToken(token); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for token %q, got: %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	userPb "github.com/wahyurudiyan/go-boilerplate/api/grpc/service-user"
+	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	userSvc "github.com/wahyurudiyan/go-boilerplate/core/services/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toTokenResponse(token userDto.TokenDTO) *userPb.TokenResponse {
+	res := &userPb.TokenResponse{
+		Token:        token.Token,
+		RefreshToken: token.RefreshToken,
+	}
+	if token.ExpireAt != nil {
+		res.ExpireAt = timestamppb.New(*token.ExpireAt)
+	}
+	if token.RefreshExpireAt != nil {
+		res.RefreshExpireAt = timestamppb.New(*token.RefreshExpireAt)
+	}
+	return res
+}
+
+func (h *grpcHandler) Login(ctx context.Context, m *userPb.LoginRequest) (*userPb.TokenResponse, error) {
+	loginDto := userDto.LoginDTO{
+		Email:    m.GetEmail(),
+		Username: m.GetUsername(),
+		Password: m.GetPassword(),
+	}
+	if err := validation.Struct(loginDto); err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	token, err := h.userService.Login(ctx, loginDto)
+	if err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	return toTokenResponse(token), nil
+}
+
+func (h *grpcHandler) RefreshToken(ctx context.Context, m *userPb.RefreshTokenRequest) (*userPb.TokenResponse, error) {
+	refreshDto := userDto.RefreshTokenDTO{RefreshToken: m.GetRefreshToken()}
+	if err := validation.Struct(refreshDto); err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	token, err := h.userService.RefreshToken(ctx, refreshDto)
+	if err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	return toTokenResponse(token), nil
+}
+
+func (h *grpcHandler) Logout(ctx context.Context, m *userPb.LogoutRequest) (*userPb.LogoutResponse, error) {
+	refreshDto := userDto.RefreshTokenDTO{RefreshToken: m.GetRefreshToken()}
+	if err := validation.Struct(refreshDto); err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	if err := h.userService.Logout(ctx, refreshDto); err != nil {
+		// Logout is public, invalid refresh token is a bad argument instead of failed authentication
+		if errors.Is(err, userSvc.ErrInvalidRefreshToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, errs.GRPCError(err)
+	}
+
+	return &userPb.LogoutResponse{}, nil
+}
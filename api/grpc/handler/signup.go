@@ -5,6 +5,8 @@ import (
 
 	userPb "github.com/wahyurudiyan/go-boilerplate/api/grpc/service-user"
 	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
 )
 
 func (h *grpcHandler) SignUp(ctx context.Context, m *userPb.SignUpRequest) (*userPb.SignUpResponse, error) {
@@ -15,10 +17,14 @@ func (h *grpcHandler) SignUp(ctx context.Context, m *userPb.SignUpRequest) (*use
 		Username: m.GetUsername(),
 		Password: m.GetPassword(),
 	}
+	if err := validation.Struct(userDto); err != nil {
+		return nil, errs.GRPCError(err)
+	}
 
-	if err := h.userService.SignUp(ctx, userDto); err != nil {
-		return nil, err
+	user, err := h.userService.SignUp(ctx, userDto)
+	if err != nil {
+		return nil, errs.GRPCError(err)
 	}
 
-	return &userPb.SignUpResponse{}, nil
+	return &userPb.SignUpResponse{UniqueId: user.UniqueId}, nil
 }
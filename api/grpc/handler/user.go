@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	userPb "github.com/wahyurudiyan/go-boilerplate/api/grpc/service-user"
+	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errInvalidPageToken = status.Error(codes.InvalidArgument, "page token is invalid")
+
+// encodePageToken returns opaque token pointing to the given page, client must
+// send the same page size along with the token
+func encodePageToken(page int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(page)))
+}
+
+// decodePageToken returns page number of the token, empty token is the first page
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 1, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+
+	page, err := strconv.Atoi(string(raw))
+	if err != nil || page < 1 {
+		return 0, errInvalidPageToken
+	}
+
+	return page, nil
+}
+
+func toUserResponse(user userDto.UserDTO) *userPb.User {
+	return &userPb.User{
+		UniqueId:  user.UniqueId,
+		Role:      user.Role,
+		Email:     user.Email,
+		Fullname:  user.Fullname,
+		Username:  user.Username,
+		Status:    user.Status,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+}
+
+func (h *grpcHandler) GetUser(ctx context.Context, m *userPb.GetUserRequest) (*userPb.User, error) {
+	if m.GetUniqueId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "unique_id is required")
+	}
+
+	user, err := h.userService.GetUser(ctx, m.GetUniqueId())
+	if err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	return toUserResponse(user), nil
+}
+
+func (h *grpcHandler) ListUsers(ctx context.Context, m *userPb.ListUsersRequest) (*userPb.ListUsersResponse, error) {
+	page, err := decodePageToken(m.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	query := userDto.ListUserDTO{Page: page, PageSize: int(m.GetPageSize())}
+	if err := validation.Struct(query); err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	users, err := h.userService.ListUsers(ctx, query)
+	if err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	res := &userPb.ListUsersResponse{Users: make([]*userPb.User, 0, len(users.Users))}
+	for _, user := range users.Users {
+		res.Users = append(res.Users, toUserResponse(user))
+	}
+	if users.HasNext {
+		res.NextPageToken = encodePageToken(users.Page + 1)
+	}
+
+	return res, nil
+}
+
+func (h *grpcHandler) UpdateUser(ctx context.Context, m *userPb.UpdateUserRequest) (*userPb.User, error) {
+	if m.GetUniqueId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "unique_id is required")
+	}
+
+	update := userDto.UpdateUserDTO{
+		Role:     m.Role,
+		Email:    m.Email,
+		Fullname: m.Fullname,
+		Username: m.Username,
+		Password: m.Password,
+	}
+	if err := validation.Struct(update); err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	user, err := h.userService.UpdateUser(ctx, m.GetUniqueId(), update)
+	if err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	return toUserResponse(user), nil
+}
+
+func (h *grpcHandler) DeleteUser(ctx context.Context, m *userPb.DeleteUserRequest) (*userPb.DeleteUserResponse, error) {
+	if m.GetUniqueId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "unique_id is required")
+	}
+
+	if err := h.userService.DeleteUser(ctx, m.GetUniqueId()); err != nil {
+		return nil, errs.GRPCError(err)
+	}
+
+	return &userPb.DeleteUserResponse{}, nil
+}
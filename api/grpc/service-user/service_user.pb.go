@@ -9,6 +9,7 @@ package serviceuser
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -99,6 +100,7 @@ func (x *SignUpRequest) GetPassword() string {
 
 type SignUpResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=UniqueId,proto3" json:"UniqueId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,20 +135,748 @@ func (*SignUpResponse) Descriptor() ([]byte, []int) {
 	return file_service_user_proto_rawDescGZIP(), []int{1}
 }
 
+func (x *SignUpResponse) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=UniqueId,proto3" json:"UniqueId,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=Role,proto3" json:"Role,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=Email,proto3" json:"Email,omitempty"`
+	Fullname      string                 `protobuf:"bytes,4,opt,name=Fullname,proto3" json:"Fullname,omitempty"`
+	Username      string                 `protobuf:"bytes,5,opt,name=Username,proto3" json:"Username,omitempty"`
+	Status        bool                   `protobuf:"varint,6,opt,name=Status,proto3" json:"Status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_service_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *User) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *User) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetFullname() string {
+	if x != nil {
+		return x.Fullname
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=UniqueId,proto3" json:"UniqueId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_service_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUserRequest) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_service_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=Users,proto3" json:"Users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=NextPageToken,proto3" json:"NextPageToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_service_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// UpdateUserRequest is a partial update, unset field is left unchanged
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=UniqueId,proto3" json:"UniqueId,omitempty"`
+	Role          *string                `protobuf:"bytes,2,opt,name=Role,proto3,oneof" json:"Role,omitempty"`
+	Email         *string                `protobuf:"bytes,3,opt,name=Email,proto3,oneof" json:"Email,omitempty"`
+	Fullname      *string                `protobuf:"bytes,4,opt,name=Fullname,proto3,oneof" json:"Fullname,omitempty"`
+	Username      *string                `protobuf:"bytes,5,opt,name=Username,proto3,oneof" json:"Username,omitempty"`
+	Password      *string                `protobuf:"bytes,6,opt,name=Password,proto3,oneof" json:"Password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_service_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateUserRequest) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetRole() string {
+	if x != nil && x.Role != nil {
+		return *x.Role
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetFullname() string {
+	if x != nil && x.Fullname != nil {
+		return *x.Fullname
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=UniqueId,proto3" json:"UniqueId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_service_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteUserRequest) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+type DeleteUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_service_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserResponse) ProtoMessage() {}
+
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{8}
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=Email,proto3" json:"Email,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=Password,proto3" json:"Password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_service_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type TokenResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Token           string                 `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	ExpireAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=ExpireAt,proto3" json:"ExpireAt,omitempty"`
+	RefreshToken    string                 `protobuf:"bytes,3,opt,name=RefreshToken,proto3" json:"RefreshToken,omitempty"`
+	RefreshExpireAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=RefreshExpireAt,proto3" json:"RefreshExpireAt,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TokenResponse) Reset() {
+	*x = TokenResponse{}
+	mi := &file_service_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenResponse) ProtoMessage() {}
+
+func (x *TokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenResponse.ProtoReflect.Descriptor instead.
+func (*TokenResponse) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TokenResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *TokenResponse) GetExpireAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return nil
+}
+
+func (x *TokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *TokenResponse) GetRefreshExpireAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshExpireAt
+	}
+	return nil
+}
+
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=RefreshToken,proto3" json:"RefreshToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_service_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LogoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=RefreshToken,proto3" json:"RefreshToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_service_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LogoutRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LogoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	mi := &file_service_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResponse) ProtoMessage() {}
+
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_service_user_proto_rawDescGZIP(), []int{13}
+}
+
 var File_service_user_proto protoreflect.FileDescriptor
 
 const file_service_user_proto_rawDesc = "" +
 	"\n" +
-	"\x12service_user.proto\x12\vserviceuser\"\x8d\x01\n" +
+	"\x12service_user.proto\x12\vserviceuser\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\x01\n" +
 	"\rSignUpRequest\x12\x12\n" +
 	"\x04Role\x18\x01 \x01(\tR\x04Role\x12\x14\n" +
 	"\x05Email\x18\x02 \x01(\tR\x05Email\x12\x1a\n" +
 	"\bFullname\x18\x03 \x01(\tR\bFullname\x12\x1a\n" +
 	"\bUsername\x18\x04 \x01(\tR\bUsername\x12\x1a\n" +
-	"\bPassword\x18\x05 \x01(\tR\bPassword\"\x10\n" +
-	"\x0eSignUpResponse2P\n" +
+	"\bPassword\x18\x05 \x01(\tR\bPassword\",\n" +
+	"\x0eSignUpResponse\x12\x1a\n" +
+	"\bUniqueId\x18\x01 \x01(\tR\bUniqueId\"\x90\x02\n" +
+	"\x04User\x12\x1a\n" +
+	"\bUniqueId\x18\x01 \x01(\tR\bUniqueId\x12\x12\n" +
+	"\x04Role\x18\x02 \x01(\tR\x04Role\x12\x14\n" +
+	"\x05Email\x18\x03 \x01(\tR\x05Email\x12\x1a\n" +
+	"\bFullname\x18\x04 \x01(\tR\bFullname\x12\x1a\n" +
+	"\bUsername\x18\x05 \x01(\tR\bUsername\x12\x16\n" +
+	"\x06Status\x18\x06 \x01(\bR\x06Status\x128\n" +
+	"\tCreatedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\x128\n" +
+	"\tUpdatedAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tUpdatedAt\",\n" +
+	"\x0eGetUserRequest\x12\x1a\n" +
+	"\bUniqueId\x18\x01 \x01(\tR\bUniqueId\"L\n" +
+	"\x10ListUsersRequest\x12\x1a\n" +
+	"\bPageSize\x18\x01 \x01(\x05R\bPageSize\x12\x1c\n" +
+	"\tPageToken\x18\x02 \x01(\tR\tPageToken\"b\n" +
+	"\x11ListUsersResponse\x12'\n" +
+	"\x05Users\x18\x01 \x03(\v2\x11.serviceuser.UserR\x05Users\x12$\n" +
+	"\rNextPageToken\x18\x02 \x01(\tR\rNextPageToken\"\x80\x02\n" +
+	"\x11UpdateUserRequest\x12\x1a\n" +
+	"\bUniqueId\x18\x01 \x01(\tR\bUniqueId\x12\x17\n" +
+	"\x04Role\x18\x02 \x01(\tH\x00R\x04Role\x88\x01\x01\x12\x19\n" +
+	"\x05Email\x18\x03 \x01(\tH\x01R\x05Email\x88\x01\x01\x12\x1f\n" +
+	"\bFullname\x18\x04 \x01(\tH\x02R\bFullname\x88\x01\x01\x12\x1f\n" +
+	"\bUsername\x18\x05 \x01(\tH\x03R\bUsername\x88\x01\x01\x12\x1f\n" +
+	"\bPassword\x18\x06 \x01(\tH\x04R\bPassword\x88\x01\x01B\a\n" +
+	"\x05_RoleB\b\n" +
+	"\x06_EmailB\v\n" +
+	"\t_FullnameB\v\n" +
+	"\t_UsernameB\v\n" +
+	"\t_Password\"/\n" +
+	"\x11DeleteUserRequest\x12\x1a\n" +
+	"\bUniqueId\x18\x01 \x01(\tR\bUniqueId\"\x14\n" +
+	"\x12DeleteUserResponse\"\\\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05Email\x18\x01 \x01(\tR\x05Email\x12\x1a\n" +
+	"\bUsername\x18\x02 \x01(\tR\bUsername\x12\x1a\n" +
+	"\bPassword\x18\x03 \x01(\tR\bPassword\"\xc7\x01\n" +
+	"\rTokenResponse\x12\x14\n" +
+	"\x05Token\x18\x01 \x01(\tR\x05Token\x126\n" +
+	"\bExpireAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bExpireAt\x12\"\n" +
+	"\fRefreshToken\x18\x03 \x01(\tR\fRefreshToken\x12D\n" +
+	"\x0fRefreshExpireAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0fRefreshExpireAt\"9\n" +
+	"\x13RefreshTokenRequest\x12\"\n" +
+	"\fRefreshToken\x18\x01 \x01(\tR\fRefreshToken\"3\n" +
+	"\rLogoutRequest\x12\"\n" +
+	"\fRefreshToken\x18\x01 \x01(\tR\fRefreshToken\"\x10\n" +
+	"\x0eLogoutResponse2\xb8\x04\n" +
 	"\vServiceUser\x12A\n" +
-	"\x06SignUp\x12\x1a.serviceuser.SignUpRequest\x1a\x1b.serviceuser.SignUpResponseB=Z;github.com/wahyurudiyan/go-bolierplate/api/grpc/serviceuserb\x06proto3"
+	"\x06SignUp\x12\x1a.serviceuser.SignUpRequest\x1a\x1b.serviceuser.SignUpResponse\x12>\n" +
+	"\x05Login\x12\x19.serviceuser.LoginRequest\x1a\x1a.serviceuser.TokenResponse\x12L\n" +
+	"\fRefreshToken\x12 .serviceuser.RefreshTokenRequest\x1a\x1a.serviceuser.TokenResponse\x12A\n" +
+	"\x06Logout\x12\x1a.serviceuser.LogoutRequest\x1a\x1b.serviceuser.LogoutResponse\x129\n" +
+	"\aGetUser\x12\x1b.serviceuser.GetUserRequest\x1a\x11.serviceuser.User\x12J\n" +
+	"\tListUsers\x12\x1d.serviceuser.ListUsersRequest\x1a\x1e.serviceuser.ListUsersResponse\x12?\n" +
+	"\n" +
+	"UpdateUser\x12\x1e.serviceuser.UpdateUserRequest\x1a\x11.serviceuser.User\x12M\n" +
+	"\n" +
+	"DeleteUser\x12\x1e.serviceuser.DeleteUserRequest\x1a\x1f.serviceuser.DeleteUserResponseB=Z;github.com/wahyurudiyan/go-bolierplate/api/grpc/serviceuserb\x06proto3"
 
 var (
 	file_service_user_proto_rawDescOnce sync.Once
@@ -160,19 +890,51 @@ func file_service_user_proto_rawDescGZIP() []byte {
 	return file_service_user_proto_rawDescData
 }
 
-var file_service_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_service_user_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_service_user_proto_goTypes = []any{
-	(*SignUpRequest)(nil),  // 0: serviceuser.SignUpRequest
-	(*SignUpResponse)(nil), // 1: serviceuser.SignUpResponse
+	(*SignUpRequest)(nil),         // 0: serviceuser.SignUpRequest
+	(*SignUpResponse)(nil),        // 1: serviceuser.SignUpResponse
+	(*User)(nil),                  // 2: serviceuser.User
+	(*GetUserRequest)(nil),        // 3: serviceuser.GetUserRequest
+	(*ListUsersRequest)(nil),      // 4: serviceuser.ListUsersRequest
+	(*ListUsersResponse)(nil),     // 5: serviceuser.ListUsersResponse
+	(*UpdateUserRequest)(nil),     // 6: serviceuser.UpdateUserRequest
+	(*DeleteUserRequest)(nil),     // 7: serviceuser.DeleteUserRequest
+	(*DeleteUserResponse)(nil),    // 8: serviceuser.DeleteUserResponse
+	(*LoginRequest)(nil),          // 9: serviceuser.LoginRequest
+	(*TokenResponse)(nil),         // 10: serviceuser.TokenResponse
+	(*RefreshTokenRequest)(nil),   // 11: serviceuser.RefreshTokenRequest
+	(*LogoutRequest)(nil),         // 12: serviceuser.LogoutRequest
+	(*LogoutResponse)(nil),        // 13: serviceuser.LogoutResponse
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
 }
 var file_service_user_proto_depIdxs = []int32{
-	0, // 0: serviceuser.ServiceUser.SignUp:input_type -> serviceuser.SignUpRequest
-	1, // 1: serviceuser.ServiceUser.SignUp:output_type -> serviceuser.SignUpResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	14, // 0: serviceuser.User.CreatedAt:type_name -> google.protobuf.Timestamp
+	14, // 1: serviceuser.User.UpdatedAt:type_name -> google.protobuf.Timestamp
+	2,  // 2: serviceuser.ListUsersResponse.Users:type_name -> serviceuser.User
+	14, // 3: serviceuser.TokenResponse.ExpireAt:type_name -> google.protobuf.Timestamp
+	14, // 4: serviceuser.TokenResponse.RefreshExpireAt:type_name -> google.protobuf.Timestamp
+	0,  // 5: serviceuser.ServiceUser.SignUp:input_type -> serviceuser.SignUpRequest
+	9,  // 6: serviceuser.ServiceUser.Login:input_type -> serviceuser.LoginRequest
+	11, // 7: serviceuser.ServiceUser.RefreshToken:input_type -> serviceuser.RefreshTokenRequest
+	12, // 8: serviceuser.ServiceUser.Logout:input_type -> serviceuser.LogoutRequest
+	3,  // 9: serviceuser.ServiceUser.GetUser:input_type -> serviceuser.GetUserRequest
+	4,  // 10: serviceuser.ServiceUser.ListUsers:input_type -> serviceuser.ListUsersRequest
+	6,  // 11: serviceuser.ServiceUser.UpdateUser:input_type -> serviceuser.UpdateUserRequest
+	7,  // 12: serviceuser.ServiceUser.DeleteUser:input_type -> serviceuser.DeleteUserRequest
+	1,  // 13: serviceuser.ServiceUser.SignUp:output_type -> serviceuser.SignUpResponse
+	10, // 14: serviceuser.ServiceUser.Login:output_type -> serviceuser.TokenResponse
+	10, // 15: serviceuser.ServiceUser.RefreshToken:output_type -> serviceuser.TokenResponse
+	13, // 16: serviceuser.ServiceUser.Logout:output_type -> serviceuser.LogoutResponse
+	2,  // 17: serviceuser.ServiceUser.GetUser:output_type -> serviceuser.User
+	5,  // 18: serviceuser.ServiceUser.ListUsers:output_type -> serviceuser.ListUsersResponse
+	2,  // 19: serviceuser.ServiceUser.UpdateUser:output_type -> serviceuser.User
+	8,  // 20: serviceuser.ServiceUser.DeleteUser:output_type -> serviceuser.DeleteUserResponse
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_service_user_proto_init() }
@@ -180,13 +942,14 @@ func file_service_user_proto_init() {
 	if File_service_user_proto != nil {
 		return
 	}
+	file_service_user_proto_msgTypes[6].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_user_proto_rawDesc), len(file_service_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,10 +1,16 @@
 package controller
 
-import userSvc "github.com/wahyurudiyan/go-boilerplate/core/services/user"
+import (
+	userSvc "github.com/wahyurudiyan/go-boilerplate/core/services/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/health"
+)
 
 type ControllerBootstrap struct {
 	// Service interfaces
 	UserService userSvc.IUserServices
+
+	// Health registry of the service dependencies
+	Health *health.Registry
 }
 
 func Bootstrap(cb ControllerBootstrap) *ControllerBootstrap {
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/health"
+)
+
+// Livez is an controller endpoint that report whether the process is alive, it is
+// served as /livez outside of the API base path so it is not part of the API docs.
+func (b *ControllerBootstrap) Livez(c *gin.Context) {
+	report := b.Health.Live(c.Request.Context())
+	c.JSON(healthStatusCode(report), common.RESTSuccessResponse("liveness", report))
+}
+
+// Readyz is an controller endpoint that report whether the service can receive traffic,
+// it is served as /readyz and /api/v1/health-check
+// @Summary Readiness probe.
+// @Description endpoint that run every dependency check, it return 503 when critical check fails or the service is shutting down.
+// @Tags Health Check Endpoint
+// @Accept */*
+// @Produce json
+// @Success 200 {object} common.RESTBody[health.Report] "Ready"
+// @Failure 503 {object} common.RESTBody[health.Report] "Not ready"
+// @Router /health-check [GET]
+func (b *ControllerBootstrap) Readyz(c *gin.Context) {
+	report := b.Health.Ready(c.Request.Context())
+	c.JSON(healthStatusCode(report), common.RESTSuccessResponse("readiness", report))
+}
+
+func healthStatusCode(report health.Report) int {
+	if report.IsUp() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	userDTO "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
+)
+
+// Login is an controller endpoint that authenticate end-user and issue PASETO token
+// @Summary Login user endpoint.
+// @Description endpoint that authenticate user by email or username and password.
+// @Tags User Endpoint
+// @Accept json
+// @Param request body userDTO.LoginDTO true "Request Body"
+// @Produce json
+// @Success 200 {object} common.RESTBody[userDTO.TokenDTO] "Success"
+// @Failure 400 {object} common.RESTBody[any] "Bad request"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Failure 429 {object} common.RESTBody[any] "Too many requests"
+// @Router /users/login [POST]
+func (b *ControllerBootstrap) Login(c *gin.Context) {
+	var body userDTO.LoginDTO
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, "incoming request body invalid"))
+		return
+	}
+
+	if err := validation.Struct(body); err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	token, err := b.UserService.Login(c.Request.Context(), body)
+	if err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse("login success", token))
+}
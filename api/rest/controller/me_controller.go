@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// Me is an controller endpoint that return the authenticated subject of the token
+// @Summary Authenticated user endpoint.
+// @Description endpoint that return unique_id and role of the bearer token owner.
+// @Tags User Endpoint
+// @Accept */*
+// @Param Authorization header string true "Bearer token"
+// @Produce json
+// @Success 200 {object} common.RESTBody[auth.Subject] "Success"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Router /users/me [GET]
+func (b *ControllerBootstrap) Me(c *gin.Context) {
+	subject, ok := auth.SubjectFromContext(c.Request.Context())
+	if !ok {
+		c.JSON(errs.ToREST(auth.ErrMissingToken))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse("authenticated user", subject))
+}
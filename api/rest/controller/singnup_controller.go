@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	userDTO "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
 )
 
 // SignUp is an controller endpoint that handle request from RESTFul by end-user
@@ -13,23 +15,34 @@ import (
 // @Description endpoint that handle user register.
 // @Tags User Endpoint
 // @Accept */*
-// @Param Authorization header string true "Request body type"
+// @Param Authorization header string false "Bearer token, required to grant non-default role"
+// @Param Idempotency-Key header string false "Unique key of the request, retry with the same key replays the first response"
 // @Param request body userDTO.SignUpDTO true "Request Body"
 // @Produce json
-// @Success 200 {object} common.RESTBody[any] "Success"
+// @Success 201 {object} common.RESTBody[userDTO.UserDTO] "Created"
 // @Failure 400 {object} common.RESTBody[any] "Bad request"
+// @Failure 403 {object} common.RESTBody[any] "Forbidden"
+// @Failure 409 {object} common.RESTBody[any] "Conflict"
+// @Failure 422 {object} common.RESTBody[any] "Idempotency key reused with different request"
+// @Failure 429 {object} common.RESTBody[any] "Too many requests"
 // @Router /users/signup [POST]
 func (b *ControllerBootstrap) SignUp(c *gin.Context) {
 	var body userDTO.SignUpDTO
 	if err := c.BindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](1022, "incoming request body invalid"))
+		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, "incoming request body invalid"))
 		return
 	}
 
-	if err := b.UserService.SignUp(c.Request.Context(), body); err != nil {
-		c.JSON(http.StatusInternalServerError, common.RESTErrorResponse[any](1034, err.Error()))
+	if err := validation.Struct(body); err != nil {
+		c.JSON(errs.ToREST(err))
 		return
 	}
 
-	c.JSON(http.StatusCreated, common.RESTSuccessResponse[any]("sign-up success", nil))
+	user, err := b.UserService.SignUp(c.Request.Context(), body)
+	if err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, common.RESTSuccessResponse("sign-up success", user))
 }
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	userDTO "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	userSvc "github.com/wahyurudiyan/go-boilerplate/core/services/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
+)
+
+// RefreshToken is an controller endpoint that rotate refresh token and issue new access token
+// @Summary Refresh token endpoint.
+// @Description endpoint that exchange refresh token with new access and refresh token, the old refresh token cannot be used anymore.
+// @Tags User Endpoint
+// @Accept json
+// @Param request body userDTO.RefreshTokenDTO true "Request Body"
+// @Produce json
+// @Success 200 {object} common.RESTBody[userDTO.TokenDTO] "Success"
+// @Failure 400 {object} common.RESTBody[any] "Bad request"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Router /users/token/refresh [POST]
+func (b *ControllerBootstrap) RefreshToken(c *gin.Context) {
+	var body userDTO.RefreshTokenDTO
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, "incoming request body invalid"))
+		return
+	}
+
+	if err := validation.Struct(body); err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	token, err := b.UserService.RefreshToken(c.Request.Context(), body)
+	if err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse("refresh token success", token))
+}
+
+// Logout is an controller endpoint that revoke refresh token
+// @Summary Logout user endpoint.
+// @Description endpoint that revoke refresh token and every token rotated from the same login.
+// @Tags User Endpoint
+// @Accept json
+// @Param request body userDTO.RefreshTokenDTO true "Request Body"
+// @Produce json
+// @Success 200 {object} common.RESTBody[any] "Success"
+// @Failure 400 {object} common.RESTBody[any] "Bad request"
+// @Router /users/logout [POST]
+func (b *ControllerBootstrap) Logout(c *gin.Context) {
+	var body userDTO.RefreshTokenDTO
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, "incoming request body invalid"))
+		return
+	}
+
+	if err := validation.Struct(body); err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	if err := b.UserService.Logout(c.Request.Context(), body); err != nil {
+		// Logout is public, invalid refresh token is a bad request instead of failed authentication
+		if errors.Is(err, userSvc.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, err.Error()))
+			return
+		}
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse[any]("logout success", nil))
+}
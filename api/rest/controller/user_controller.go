@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	userDTO "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/validation"
+)
+
+// GetUser is an controller endpoint that return a single user
+// @Summary Get user endpoint.
+// @Description endpoint that return user by unique_id, other than own account requires users:read permission.
+// @Tags User Endpoint
+// @Accept */*
+// @Param Authorization header string true "Bearer token"
+// @Param unique_id path string true "User unique id"
+// @Produce json
+// @Success 200 {object} common.RESTBody[userDTO.UserDTO] "Success"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Failure 403 {object} common.RESTBody[any] "Forbidden"
+// @Failure 404 {object} common.RESTBody[any] "Not found"
+// @Router /users/{unique_id} [GET]
+func (b *ControllerBootstrap) GetUser(c *gin.Context) {
+	user, err := b.UserService.GetUser(c.Request.Context(), c.Param("unique_id"))
+	if err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse("get user success", user))
+}
+
+// ListUsers is an controller endpoint that return paginated users
+// @Summary List users endpoint.
+// @Description endpoint that return active users ordered by creation, requires users:read permission.
+// @Tags User Endpoint
+// @Accept */*
+// @Param Authorization header string true "Bearer token"
+// @Param page query int false "Page number, start from 1"
+// @Param page_size query int false "Page size, maximum 100"
+// @Produce json
+// @Success 200 {object} common.RESTBody[userDTO.UserPageDTO] "Success"
+// @Failure 400 {object} common.RESTBody[any] "Bad request"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Failure 403 {object} common.RESTBody[any] "Forbidden"
+// @Router /users [GET]
+func (b *ControllerBootstrap) ListUsers(c *gin.Context) {
+	var query userDTO.ListUserDTO
+	if err := c.BindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, "incoming request query invalid"))
+		return
+	}
+
+	if err := validation.Struct(query); err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	page, err := b.UserService.ListUsers(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse("list users success", page))
+}
+
+// UpdateUser is an controller endpoint that partially update a user
+// @Summary Update user endpoint.
+// @Description endpoint that update given fields of a user, other than own account requires users:write permission and changing role requires users:grant-role permission.
+// @Tags User Endpoint
+// @Accept json
+// @Param Authorization header string true "Bearer token"
+// @Param unique_id path string true "User unique id"
+// @Param Idempotency-Key header string false "Unique key of the request, retry with the same key replays the first response"
+// @Param request body userDTO.UpdateUserDTO true "Request Body"
+// @Produce json
+// @Success 200 {object} common.RESTBody[userDTO.UserDTO] "Success"
+// @Failure 400 {object} common.RESTBody[any] "Bad request"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Failure 403 {object} common.RESTBody[any] "Forbidden"
+// @Failure 404 {object} common.RESTBody[any] "Not found"
+// @Failure 409 {object} common.RESTBody[any] "Conflict"
+// @Failure 422 {object} common.RESTBody[any] "Idempotency key reused with different request"
+// @Router /users/{unique_id} [PATCH]
+func (b *ControllerBootstrap) UpdateUser(c *gin.Context) {
+	var body userDTO.UpdateUserDTO
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, common.RESTErrorResponse[any](errs.CodeValidation, "incoming request body invalid"))
+		return
+	}
+
+	if err := validation.Struct(body); err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	user, err := b.UserService.UpdateUser(c.Request.Context(), c.Param("unique_id"), body)
+	if err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse("update user success", user))
+}
+
+// DeleteUser is an controller endpoint that soft delete a user
+// @Summary Delete user endpoint.
+// @Description endpoint that soft delete a user, other than own account requires users:delete permission.
+// @Tags User Endpoint
+// @Accept */*
+// @Param Authorization header string true "Bearer token"
+// @Param unique_id path string true "User unique id"
+// @Param Idempotency-Key header string false "Unique key of the request, retry with the same key replays the first response"
+// @Produce json
+// @Success 200 {object} common.RESTBody[any] "Success"
+// @Failure 401 {object} common.RESTBody[any] "Unauthorized"
+// @Failure 403 {object} common.RESTBody[any] "Forbidden"
+// @Failure 404 {object} common.RESTBody[any] "Not found"
+// @Failure 409 {object} common.RESTBody[any] "Conflict"
+// @Failure 422 {object} common.RESTBody[any] "Idempotency key reused with different request"
+// @Router /users/{unique_id} [DELETE]
+func (b *ControllerBootstrap) DeleteUser(c *gin.Context) {
+	if err := b.UserService.DeleteUser(c.Request.Context(), c.Param("unique_id")); err != nil {
+		c.JSON(errs.ToREST(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.RESTSuccessResponse[any]("delete user success", nil))
+}
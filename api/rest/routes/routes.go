@@ -5,17 +5,34 @@ import (
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/wahyurudiyan/go-boilerplate/api/rest/controller"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
 	"github.com/wahyurudiyan/go-boilerplate/docs" // change with your own project docs path
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
 	// _ "github.com/wahyurudiyan/go-boilerplate/docs" // change with your own project docs path
 )
 
+// Middlewares are built by the app from its config
+type Middlewares struct {
+	// RateLimit is applied to every user route once
+	RateLimit gin.HandlerFunc
+	// Idempotency is applied to mutating user routes, after authentication
+	Idempotency gin.HandlerFunc
+}
+
 type routerBootstrap struct {
-	controller *controller.ControllerBootstrap
+	rbac          *rbac.Registry
+	controller    *controller.ControllerBootstrap
+	middlewares   Middlewares
+	tokenVerifier auth.ITokenVerifier
 }
 
-func NewRouter(c *controller.ControllerBootstrap) *routerBootstrap {
+func NewRouter(c *controller.ControllerBootstrap, tokenVerifier auth.ITokenVerifier, rbac *rbac.Registry, middlewares Middlewares) *routerBootstrap {
 	return &routerBootstrap{
-		controller: c,
+		rbac:          rbac,
+		controller:    c,
+		middlewares:   middlewares,
+		tokenVerifier: tokenVerifier,
 	}
 }
 
@@ -24,14 +41,32 @@ func (r *routerBootstrap) swaggerAPIDoc(router *gin.Engine) {
 }
 
 func (r *routerBootstrap) Routes(router *gin.Engine) {
+	// Probes are served outside of the API base path
+	router.GET("/livez", r.controller.Livez)
+	router.GET("/readyz", r.controller.Readyz)
+
 	// Init base path
 	rootPathV1 := router.Group("/api/v1")
-	rootPathV1.GET("/health-check", controller.HealthCheck)
+	rootPathV1.GET("/health-check", r.controller.Readyz)
 
 	// Init swagger endpoint
 	docs.SwaggerInfo.BasePath = rootPathV1.BasePath()
 	r.swaggerAPIDoc(router)
 
 	userRoutes := rootPathV1.Group("/users")
-	userRoutes.POST("/signup", r.controller.SignUp)
+	publicUserRoutes := userRoutes.Group("", r.middlewares.RateLimit)
+	publicUserRoutes.POST("/signup", auth.GinOptionalMiddleware(r.tokenVerifier), r.middlewares.Idempotency, r.controller.SignUp)
+	publicUserRoutes.POST("/login", r.controller.Login)
+	publicUserRoutes.POST("/logout", r.controller.Logout)
+	// Refresh is not idempotent, stored response would keep the rotated tokens in Redis and
+	// replay them to anyone holding the old refresh token
+	publicUserRoutes.POST("/token/refresh", r.controller.RefreshToken)
+
+	// Routes below require valid bearer token, so they are limited per subject
+	authUserRoutes := userRoutes.Group("", auth.GinMiddleware(r.tokenVerifier), r.middlewares.RateLimit)
+	authUserRoutes.GET("/me", r.controller.Me)
+	authUserRoutes.GET("", rbac.RequirePermission(r.rbac, userEnt.PermissionUserRead), r.controller.ListUsers)
+	authUserRoutes.GET("/:unique_id", r.controller.GetUser)
+	authUserRoutes.PATCH("/:unique_id", r.middlewares.Idempotency, r.controller.UpdateUser)
+	authUserRoutes.DELETE("/:unique_id", r.middlewares.Idempotency, r.controller.DeleteUser)
 }
@@ -2,56 +2,378 @@ package app
 
 import (
 	"context"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
 
+	"aidanwoods.dev/go-paseto"
 	"github.com/jmoiron/sqlx"
+	goRedis "github.com/redis/go-redis/v9"
+	userPb "github.com/wahyurudiyan/go-boilerplate/api/grpc/service-user"
 	"github.com/wahyurudiyan/go-boilerplate/config"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	tokenRepo "github.com/wahyurudiyan/go-boilerplate/core/repositories/token"
 	userRepo "github.com/wahyurudiyan/go-boilerplate/core/repositories/user"
 	userSvc "github.com/wahyurudiyan/go-boilerplate/core/services/user"
+	"github.com/wahyurudiyan/go-boilerplate/migrations"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/configz"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/graceful"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/health"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/outbox"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/ratelimit"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/telemetry"
 )
 
+// Name of the components managed by graceful.Run
+const (
+	ComponentTelemetry = "OPENTELEMETRY"
+	ComponentDatabase  = "DATABASE"
+	ComponentRedis     = "REDIS"
+	ComponentREST      = "REST"
+	ComponentGRPC      = "GRPC"
+	ComponentHealth    = "HEALTH"
+	ComponentOutbox    = "OUTBOX_RELAY"
+	ComponentConfig    = "CONFIG_WATCHER"
+)
+
+// serverDependencies must outlive the servers, so in-flight request can still use
+// them and export its spans while the servers are draining
+var serverDependencies = []string{ComponentTelemetry, ComponentDatabase, ComponentRedis}
+
+// configPinger is a remote configuration source which reports its health
+type configPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// configRenewer is a remote configuration source which renews its credential (e.g. Vault token)
+type configRenewer interface {
+	RenewToken(ctx context.Context) error
+}
+
 type appBoostraper struct {
-	db          *sqlx.DB
-	cfg         *config.ServiceConfig
-	userService userSvc.IUserServices
+	db            *sqlx.DB
+	redis         goRedis.UniversalClient
+	cfg           *config.ServiceConfig
+	configWatcher *configz.Watcher[config.ServiceConfig]
+	configSources []configz.ISource
+	health        *health.Registry
+	outboxRelay   *outbox.Relay
+	rbac          *rbac.Registry
+	rateLimiter   ratelimit.ILimiter
+	rateLimits    *ratelimit.DynamicPolicy
+	apiKeys       ratelimit.APIKeys
+	userService   userSvc.IUserServices
+	tokenVerifier auth.ITokenVerifier
 }
 
 func NewApp() *appBoostraper {
-	var cfg *config.ServiceConfig
-	vc := configz.NewVault(&configz.VaultConfig{
-		Prefix:  "user",
-		Token:   os.Getenv("VAULT_TOKEN"),
-		Address: "http://localhost:8200",
-		Timeout: time.Duration(30 * time.Second),
-	})
-	if err := vc.LoadConfig(context.Background(), "/v1/kv/data/go-boilerplate", &cfg); err != nil {
+	// Configuration sources are chosen by bootstrap environment variables, see configz.NewLoaderFromEnv
+	cfg := new(config.ServiceConfig)
+	configLoader, err := configz.NewLoaderFromEnv(os.Args[1:])
+	if err != nil {
+		panic(err)
+	}
+	if err := configLoader.Load(context.Background(), cfg); err != nil {
 		panic(err)
 	}
+	slog.Debug("[CONFIG] effective configuration", "config", configLoader.EffectiveConfig())
+
+	// Keys tagged `reload:"true"` are reloaded while serving, see ConfigWatcherComponent
+	watchConfig, err := configz.WatchConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	configWatcher := configz.NewWatcher(configLoader, cfg, watchConfig)
+	setLogLevel(cfg.LogLevel)
+	configWatcher.Subscribe(func(prev, next *config.ServiceConfig) {
+		setLogLevel(next.LogLevel)
+	}, "LOG_LEVEL")
 
 	db, err := sql.NewClient(&cfg.Database)
 	if err != nil {
 		panic(err)
 	}
 
+	if cfg.Database.DatabaseAutoMigrate {
+		if err := migrate(context.Background(), db); err != nil {
+			panic(err)
+		}
+	}
+
+	redisClient, err := redis.NewClient(&cfg.Redis)
+	if err != nil {
+		panic(err)
+	}
+
+	tokenSecretKey, err := newTokenSecretKey(cfg.TokenSecretKey)
+	if err != nil {
+		panic(err)
+	}
+
+	rbacRegistry, err := newRBACRegistry(cfg.RBAC)
+	if err != nil {
+		panic(err)
+	}
+
+	rateLimitPolicy, err := newRateLimitPolicy(cfg.RateLimit)
+	if err != nil {
+		panic(err)
+	}
+	rateLimits := ratelimit.NewDynamicPolicy(rateLimitPolicy)
+	configWatcher.Subscribe(func(prev, next *config.ServiceConfig) {
+		policy, err := newRateLimitPolicy(next.RateLimit)
+		if err != nil {
+			slog.Warn("[CONFIG] invalid rate limit policy, keeping current policy", "error", err)
+			return
+		}
+		rateLimits.Store(policy)
+	}, "RATELIMIT_ENABLED", "RATELIMIT_POLICY")
+	apiKeys, err := ratelimit.ParseAPIKeys(cfg.RateLimit.RateLimitAPIKeys)
+	if err != nil {
+		panic(err)
+	}
+	// Limits are shared by replicas through Redis and enforced per replica while Redis is down
+	rateLimiter := ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisClient, cfg.ApplicationName), ratelimit.NewMemoryLimiter())
+
+	// Outbox shares the transaction of the user repository
+	txManager := sql.NewTxManager(db, sql.TxOptions{})
+	outboxStore, err := outbox.NewSQLStore(db, txManager)
+	if err != nil {
+		panic(err)
+	}
+	outboxRelay := outbox.NewRelay(outboxStore, newOutboxPublisher(cfg, redisClient), outbox.RelayConfig{
+		Interval:  cfg.OutboxRelayInterval,
+		BatchSize: cfg.OutboxRelayBatchSize,
+	})
+
 	// User repositories contruction
-	userRepo := userRepo.NewUserSQLRepository(db)
+	userRepository, err := userRepo.NewUserSQLRepository(db)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.UserCacheEnabled {
+		userRepository, err = userRepo.NewUserCacheRepository(userRepository, redisClient, userRepo.UserCacheConfig{
+			Prefix:      cfg.ApplicationName,
+			TTL:         cfg.UserCacheTTL,
+			NegativeTTL: cfg.UserCacheNegativeTTL,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+	tokenRepo := tokenRepo.NewRefreshTokenRedisRepository(redisClient, cfg.ApplicationName)
 
 	// User services construction
 	repoDependency := userSvc.UserServicesImpl{
-		UserRepo: userRepo,
+		TokenIssuer:            cfg.TokenIssuer,
+		TokenSecretKey:         tokenSecretKey,
+		TokenExpiration:        cfg.TokenExpiration,
+		RefreshTokenExpiration: cfg.TokenRefreshExpiration,
+		RBAC:                   rbacRegistry,
+		TxManager:              txManager,
+		Outbox:                 outboxStore,
+		UserRepo:               userRepository,
+		TokenRepo:              tokenRepo,
 	}
 	userService := userSvc.NewUserService(repoDependency)
 
+	// Token verifier used by REST middleware and gRPC interceptor
+	tokenVerifier := auth.NewPasetoVerifier(auth.PasetoVerifierConfig{
+		Issuer:    cfg.TokenIssuer,
+		PublicKey: tokenSecretKey.Public(),
+	})
+
+	// Dependency checks served by readiness probe
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register(health.Check{Name: "database", Checker: health.SQLChecker(db), Critical: true})
+	healthRegistry.Register(health.Check{Name: "redis", Checker: health.RedisChecker(redisClient), Critical: true})
+	for _, source := range configLoader.Sources() {
+		// Remote configuration source (e.g. Vault) is a non critical dependency
+		if pinger, ok := source.(configPinger); ok {
+			healthRegistry.Register(health.Check{Name: source.Name(), Checker: pinger.Ping})
+		}
+	}
+	healthRegistry.Register(health.Check{Name: "otlp-collector", Checker: health.DialChecker(telemetry.CollectorAddress())})
+
 	return &appBoostraper{
-		db:          db,
-		cfg:         cfg,
-		userService: userService,
+		db:            db,
+		redis:         redisClient,
+		cfg:           cfg,
+		configWatcher: configWatcher,
+		configSources: configLoader.Sources(),
+		health:        healthRegistry,
+		outboxRelay:   outboxRelay,
+		rbac:          rbacRegistry,
+		rateLimiter:   rateLimiter,
+		rateLimits:    rateLimits,
+		apiKeys:       apiKeys,
+		userService:   userService,
+		tokenVerifier: tokenVerifier,
 	}
 }
 
+// GetServiceConfig returns the latest configuration, it must not be modified
 func (a *appBoostraper) GetServiceConfig() *config.ServiceConfig {
-	return a.cfg
+	return a.configWatcher.Current()
+}
+
+// ResourceComponents returns the shared connections, they are opened by NewApp and
+// closed after every server has stopped
+func (a *appBoostraper) ResourceComponents() []graceful.Component {
+	return []graceful.Component{
+		{
+			Name: ComponentDatabase,
+			Stop: func(ctx context.Context) error { return a.db.Close() },
+		},
+		{
+			Name: ComponentRedis,
+			Stop: func(ctx context.Context) error { return a.redis.Close() },
+		},
+	}
+}
+
+// ConfigWatcherComponent reloads configuration when its file or remote version changes,
+// and on SIGHUP
+func (a *appBoostraper) ConfigWatcherComponent() graceful.Component {
+	return a.configWatcher.Component(ComponentConfig)
+}
+
+// ConfigSourceComponents renew credential of remote configuration sources before it
+// expires, e.g. Vault token
+func (a *appBoostraper) ConfigSourceComponents() []graceful.Component {
+	var components []graceful.Component
+	for _, source := range a.configSources {
+		if renewer, ok := source.(configRenewer); ok {
+			components = append(components, graceful.Component{
+				Name: strings.ToUpper(source.Name()) + "_TOKEN_RENEWAL",
+				Run:  renewer.RenewToken,
+			})
+		}
+	}
+	return components
+}
+
+// OutboxRelayComponent publishes domain events written to the outbox, only the elected
+// replica runs the relay so events are published in order
+func (a *appBoostraper) OutboxRelayComponent() graceful.Component {
+	component := redis.LeaderComponent(redis.NewLocker(a.redis, a.cfg.ApplicationName), redis.ElectionConfig{
+		Name: ComponentOutbox,
+	}, a.outboxRelay.Run)
+	component.DependsOn = serverDependencies
+	return component
+}
+
+// HealthComponent flips readiness to failing as soon as shutdown starts. It depends on
+// the servers, so it is stopped before them and load balancer drains the traffic while
+// the servers are finishing in-flight requests.
+func (a *appBoostraper) HealthComponent() graceful.Component {
+	return graceful.Component{
+		Name:      ComponentHealth,
+		DependsOn: []string{ComponentREST, ComponentGRPC},
+		Stop: func(ctx context.Context) error {
+			a.health.Shutdown()
+			return nil
+		},
+	}
+}
+
+// setLogLevel sets level of the default logger, LOG_LEVEL is validated on load
+func setLogLevel(level string) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		slog.Warn("invalid LOG_LEVEL, keeping current level", "level", level)
+		return
+	}
+	slog.SetLogLoggerLevel(logLevel)
+}
+
+// newOutboxPublisher returns publisher configured by OUTBOX_PUBLISHER
+func newOutboxPublisher(cfg *config.ServiceConfig, redisClient goRedis.UniversalClient) outbox.IPublisher {
+	if cfg.OutboxPublisher == "log" {
+		return outbox.NewLogPublisher()
+	}
+
+	stream := cfg.OutboxStream
+	if stream == "" {
+		stream = cfg.ApplicationName + ":user_events"
+	}
+	return outbox.NewRedisStreamPublisher(redisClient, stream, cfg.OutboxStreamMaxLen)
+}
+
+// migrate applies pending migrations embedded in the binary
+func migrate(ctx context.Context, db *sqlx.DB) error {
+	dialect, err := sql.DialectOf(db.DriverName())
+	if err != nil {
+		return err
+	}
+
+	migrationSet, err := migrations.ForDialect(dialect)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := sql.NewMigrator(db, migrationSet)
+	if err != nil {
+		return err
+	}
+
+	return migrator.Up(ctx)
+}
+
+// newTokenSecretKey parse hex encoded PASETO secret key, an ephemeral key is generated
+// when it is empty so every issued token become invalid after the service restarted.
+func newTokenSecretKey(hexKey string) (paseto.V4AsymmetricSecretKey, error) {
+	if hexKey == "" {
+		slog.Warn("TOKEN_SECRET_KEY is empty, generating ephemeral token secret key")
+		return paseto.NewV4AsymmetricSecretKey(), nil
+	}
+
+	return paseto.NewV4AsymmetricSecretKeyFromHex(hexKey)
+}
+
+// newRBACRegistry builds role registry from config, built-in user roles are used
+// when the policy is not configured.
+func newRBACRegistry(cfg rbac.RBACConfig) (*rbac.Registry, error) {
+	if cfg.RBACPolicy == "" {
+		slog.Warn("RBAC_POLICY is empty, using default user policy", "policy", userEnt.DefaultRBACPolicy)
+		cfg.RBACPolicy = userEnt.DefaultRBACPolicy
+	}
+
+	if cfg.RBACDefaultRole == "" {
+		cfg.RBACDefaultRole = userEnt.RoleMember
+	}
+
+	return rbac.NewRegistry(&cfg)
+}
+
+// newRateLimitPolicy parses per-route limits from config, anonymous endpoints are protected
+// by default policy when the policy is not configured
+func newRateLimitPolicy(cfg ratelimit.RateLimitConfig) (ratelimit.Policy, error) {
+	if !cfg.RateLimitEnabled {
+		return ratelimit.Policy{}, nil
+	}
+
+	if cfg.RateLimitPolicy == "" {
+		slog.Warn("RATELIMIT_POLICY is empty, using default rate limit policy")
+		return defaultRateLimitPolicy(), nil
+	}
+
+	return ratelimit.ParsePolicy(cfg.RateLimitPolicy)
+}
+
+func defaultRateLimitPolicy() ratelimit.Policy {
+	signup := ratelimit.Rule{Algorithm: ratelimit.AlgorithmSlidingWindow, Limit: 5, Window: time.Minute}
+	login := ratelimit.Rule{Algorithm: ratelimit.AlgorithmSlidingWindow, Limit: 10, Window: time.Minute}
+	return ratelimit.Policy{
+		"POST /api/v1/users/signup":              signup,
+		"POST /api/v1/users/login":               login,
+		userPb.ServiceUser_SignUp_FullMethodName: signup,
+		userPb.ServiceUser_Login_FullMethodName:  login,
+		ratelimit.RouteDefault:                   {Algorithm: ratelimit.AlgorithmTokenBucket, Limit: 100, Window: time.Second},
+	}
 }
@@ -2,34 +2,89 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 
 	"github.com/wahyurudiyan/go-boilerplate/api/grpc/handler"
 	userPb "github.com/wahyurudiyan/go-boilerplate/api/grpc/service-user"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/graceful"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/health"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/ratelimit"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
 	"google.golang.org/grpc"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-func (a *appBoostraper) GRPCBootstrap() graceful.ExecCallback {
-	slog.Info("[GRPC] server running", "port", a.cfg.GrpcPort)
-	return func(ctx context.Context) (graceful.ShutdownCallback, error) {
-		grpcHost := fmt.Sprintf("0.0.0.0:%s", a.cfg.GrpcPort)
-		grpcListener, err := net.Listen("tcp", grpcHost)
-		if err != nil {
-			panic(err)
-		}
-
-		grpcservice := handler.NewGRPCHandler(a.userService)
-
-		grpcServer := grpc.NewServer(grpc.ConnectionTimeout(a.cfg.GrpcTimeout))
-		userPb.RegisterServiceUserServer(grpcServer, grpcservice)
-		grpcServer.Serve(grpcListener)
-		return func(ctx context.Context) error {
-			slog.Info("[GRPC] server shutting down!")
-			grpcServer.GracefulStop()
+// grpcMethodPolicies maps gRPC method to the permissions required to call it
+var grpcMethodPolicies = rbac.MethodPolicies{
+	userPb.ServiceUser_ListUsers_FullMethodName: {userEnt.PermissionUserRead},
+}
+
+func (a *appBoostraper) GRPCBootstrap() graceful.Component {
+	grpcservice := handler.NewGRPCHandler(a.userService)
+
+	publicMethods := []string{
+		userPb.ServiceUser_SignUp_FullMethodName,
+		userPb.ServiceUser_Login_FullMethodName,
+		userPb.ServiceUser_Logout_FullMethodName,
+		userPb.ServiceUser_RefreshToken_FullMethodName,
+		healthPb.Health_Check_FullMethodName,
+		healthPb.Health_Watch_FullMethodName,
+	}
+	rateLimitKey := ratelimit.GRPCKeyByAPIKey(a.cfg.RateLimit.RateLimitAPIKeyHeader, a.apiKeys)
+	grpcServer := grpc.NewServer(
+		grpc.ConnectionTimeout(a.cfg.GrpcTimeout),
+		grpc.ChainUnaryInterceptor(
+			auth.UnaryServerInterceptor(a.tokenVerifier, publicMethods...),
+			ratelimit.UnaryServerInterceptor(a.rateLimiter, a.rateLimits, rateLimitKey),
+			rbac.UnaryServerInterceptor(a.rbac, grpcMethodPolicies),
+		),
+		grpc.ChainStreamInterceptor(
+			auth.StreamServerInterceptor(a.tokenVerifier, publicMethods...),
+			ratelimit.StreamServerInterceptor(a.rateLimiter, a.rateLimits, rateLimitKey),
+			rbac.StreamServerInterceptor(a.rbac, grpcMethodPolicies),
+		),
+	)
+	userPb.RegisterServiceUserServer(grpcServer, grpcservice)
+	healthPb.RegisterHealthServer(grpcServer, health.NewGRPCServer(a.health, userPb.ServiceUser_ServiceDesc.ServiceName))
+
+	return graceful.Component{
+		Name:      ComponentGRPC,
+		DependsOn: serverDependencies,
+		Run: func(ctx context.Context) error {
+			grpcHost := fmt.Sprintf("0.0.0.0:%s", a.cfg.GrpcPort)
+			grpcListener, err := net.Listen("tcp", grpcHost)
+			if err != nil {
+				return err
+			}
+
+			slog.Info("[GRPC] server running", "port", a.cfg.GrpcPort)
+			if err := grpcServer.Serve(grpcListener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				return err
+			}
 			return nil
-		}, nil
+		},
+		Stop: func(ctx context.Context) error {
+			slog.Info("[GRPC] server shutting down!")
+
+			// Force close remaining connection when graceful stop exceed the deadline
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+				return nil
+			case <-ctx.Done():
+				grpcServer.Stop()
+				return ctx.Err()
+			}
+		},
 	}
 }
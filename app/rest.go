@@ -8,28 +8,38 @@ import (
 	"github.com/wahyurudiyan/go-boilerplate/api/rest/routes"
 	"github.com/wahyurudiyan/go-boilerplate/internal/rest"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/graceful"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/idempotency"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/ratelimit"
 )
 
-func (a *appBoostraper) RestBootstrap() graceful.ExecCallback {
+func (a *appBoostraper) RestBootstrap() graceful.Component {
 	// Controller bootstraping
 	controllerDependency := controller.ControllerBootstrap{
 		UserService: a.userService,
+		Health:      a.health,
 	}
 	controller := controller.Bootstrap(controllerDependency)
 	// Setup router
-	router := routes.NewRouter(controller)
-	return func(ctx context.Context) (graceful.ShutdownCallback, error) {
-		srv := rest.NewGinServer(a.cfg)
-		srv.RegisterRoutes(router.Routes)
+	router := routes.NewRouter(controller, a.tokenVerifier, a.rbac, routes.Middlewares{
+		RateLimit: ratelimit.GinMiddleware(a.rateLimiter, a.rateLimits, ratelimit.KeyByAPIKey(a.cfg.RateLimit.RateLimitAPIKeyHeader, a.apiKeys)),
+		Idempotency: idempotency.GinMiddleware(idempotency.NewRedisStore(a.redis, a.cfg.ApplicationName), idempotency.Config{
+			TTL:     a.cfg.IdempotencyTTL,
+			LockTTL: a.cfg.IdempotencyLockTTL,
+		}),
+	})
+	srv := rest.NewGinServer(a.cfg)
+	srv.RegisterRoutes(router.Routes)
 
-		// Run the server
-		if err := srv.Run(); err != nil {
-			slog.ErrorContext(context.Background(), "unable to run server", "error", err)
-			return nil, err
-		}
-
-		return func(ctx context.Context) error {
-			return srv.Shutdown(ctx)
-		}, nil
+	return graceful.Component{
+		Name:      ComponentREST,
+		DependsOn: serverDependencies,
+		Run: func(ctx context.Context) error {
+			if err := srv.Run(); err != nil {
+				slog.ErrorContext(ctx, "unable to run server", "error", err)
+				return err
+			}
+			return nil
+		},
+		Stop: srv.Shutdown,
 	}
 }
@@ -3,32 +3,59 @@ package config
 import (
 	"time"
 
+	"github.com/wahyurudiyan/go-boilerplate/pkg/ratelimit"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/redis"
 	"github.com/wahyurudiyan/go-boilerplate/pkg/sql"
 )
 
 type ServiceConfig struct {
 	// Application Configuration
-	ApplicationName        string `mapstructure:"APPLICATION_NAME"`
-	ApplicationEngine      string `mapstructure:"APPLICATION_EGINE"` // currently only support Gin (default) and Fiber
+	ApplicationName        string `mapstructure:"APPLICATION_NAME" validate:"required"`
+	ApplicationEngine      string `mapstructure:"APPLICATION_EGINE" default:"gin" validate:"oneof=gin fiber"` // currently only support Gin and Fiber
 	ApplicationVersion     string `mapstructure:"APPLICATION_VERSION"`
 	ApplicationTribeName   string `mapstructure:"APPLICATION_TRIBE_NAME"`
 	ApplicationEnvrionment string `mapstructure:"APPLICATION_ENVRIONMENT"`
 
-	GrpcPort    string        `mapstructure:"GRPC_PORT"`
-	GrpcTimeout time.Duration `mapstructure:"GRPC_TIMEOUT"`
+	LogLevel string `mapstructure:"LOG_LEVEL" default:"debug" validate:"oneof=debug info warn error" reload:"true"`
 
-	RestPort               string `mapstructure:"REST_PORT"`
-	RestBodyLimit          int64  `mapstructure:"REST_BODY_LIMIT"`           // [FIBER ONLY] in megabyte
-	RestStrictRoute        bool   `mapstructure:"REST_STRICT_ROUTE"`         // [FIBER ONLY] /foo and /foo/ is different when enabled
-	RestReadTimeout        int64  `mapstructure:"REST_READ_TIMEOUT"`         // [FIBER ONLY] in seconds, 0 is unlimited
-	RestWriteTimeout       int64  `mapstructure:"REST_WRITE_TIMEOUT"`        // [FIBER ONLY] in seconds, 0 is unlimited
-	RestIdleTimeout        int64  `mapstructure:"REST_IDLE_TIMEOUT"`         // [FIBER ONLY] in seconds, 0 is unlimited
-	RestRouteCaseSensitive bool   `mapstructure:"REST_ROUTE_CASE_SENSITIVE"` // [FIBER ONLY] /Foo and /foo is different when enabled
+	GrpcPort    string        `mapstructure:"GRPC_PORT" default:"9090" validate:"port"`
+	GrpcTimeout time.Duration `mapstructure:"GRPC_TIMEOUT" default:"120s" validate:"min=1s"` // connection handshake timeout
 
-	TelemetryMeterInterval      time.Duration `mapstructure:"TELEMETRY_METER_INTERVAL"`
+	RestPort               string `mapstructure:"REST_PORT" default:"8080" validate:"port"`
+	RestBodyLimit          int64  `mapstructure:"REST_BODY_LIMIT" validate:"min=0"`    // [FIBER ONLY] in megabyte
+	RestStrictRoute        bool   `mapstructure:"REST_STRICT_ROUTE"`                   // [FIBER ONLY] /foo and /foo/ is different when enabled
+	RestReadTimeout        int64  `mapstructure:"REST_READ_TIMEOUT" validate:"min=0"`  // [FIBER ONLY] in seconds, 0 is unlimited
+	RestWriteTimeout       int64  `mapstructure:"REST_WRITE_TIMEOUT" validate:"min=0"` // [FIBER ONLY] in seconds, 0 is unlimited
+	RestIdleTimeout        int64  `mapstructure:"REST_IDLE_TIMEOUT" validate:"min=0"`  // [FIBER ONLY] in seconds, 0 is unlimited
+	RestRouteCaseSensitive bool   `mapstructure:"REST_ROUTE_CASE_SENSITIVE"`           // [FIBER ONLY] /Foo and /foo is different when enabled
+	// RestTrustedProxies are IPs or CIDRs of proxies whose X-Forwarded-For is used as client IP,
+	// empty trusts no proxy and client IP is the remote address of the connection
+	RestTrustedProxies []string `mapstructure:"REST_TRUSTED_PROXIES" validate:"omitempty,dive,ip|cidr"`
+
+	TokenIssuer            string        `mapstructure:"TOKEN_ISSUER" validate:"required"`
+	TokenSecretKey         string        `mapstructure:"TOKEN_SECRET_KEY"` // hex encoded ed25519 secret key to sign v4 public PASETO
+	TokenExpiration        time.Duration `mapstructure:"TOKEN_EXPIRATION" default:"15m" validate:"min=1m"`
+	TokenRefreshExpiration time.Duration `mapstructure:"TOKEN_REFRESH_EXPIRATION" default:"168h" validate:"min=1m"`
+
+	OutboxPublisher      string        `mapstructure:"OUTBOX_PUBLISHER" default:"redis" validate:"oneof=redis log"`
+	OutboxStream         string        `mapstructure:"OUTBOX_STREAM"`                                                    // redis stream name, default is <APPLICATION_NAME>:user_events
+	OutboxStreamMaxLen   int64         `mapstructure:"OUTBOX_STREAM_MAX_LEN" validate:"min=0"`                           // approximate stream length, 0 is unlimited
+	OutboxRelayInterval  time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL" default:"1s" validate:"min=10ms"`           // outbox polling interval
+	OutboxRelayBatchSize int           `mapstructure:"OUTBOX_RELAY_BATCH_SIZE" default:"100" validate:"min=1,max=10000"` // events published per transaction
+
+	IdempotencyTTL     time.Duration `mapstructure:"IDEMPOTENCY_TTL" default:"24h" validate:"min=1m"`     // how long response of Idempotency-Key is replayed
+	IdempotencyLockTTL time.Duration `mapstructure:"IDEMPOTENCY_LOCK_TTL" default:"1m" validate:"min=1s"` // in-flight key expiry when replica crashes
+
+	UserCacheEnabled     bool          `mapstructure:"USER_CACHE_ENABLED"` // cache user lookups in Redis
+	UserCacheTTL         time.Duration `mapstructure:"USER_CACHE_TTL" default:"5m" validate:"min=1s"`
+	UserCacheNegativeTTL time.Duration `mapstructure:"USER_CACHE_NEGATIVE_TTL" default:"30s" validate:"min=1s"` // ttl of lookup without user
+
+	TelemetryMeterInterval      time.Duration `mapstructure:"TELEMETRY_METER_INTERVAL" default:"3s" validate:"min=1s"`
 	TelemetryEnableRuntimeMeter bool          `mapstructure:"TELEMETRY_ENABLE_RUNTIME_METER"`
 
-	Redis    redis.RedisConfig `mapstructure:",squash"`
-	Database sql.SQLConfig     `mapstructure:",squash"`
+	RBAC      rbac.RBACConfig           `mapstructure:",squash"`
+	Redis     redis.RedisConfig         `mapstructure:",squash"`
+	Database  sql.SQLConfig             `mapstructure:",squash"`
+	RateLimit ratelimit.RateLimitConfig `mapstructure:",squash"`
 }
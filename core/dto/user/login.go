@@ -3,13 +3,19 @@ package user
 import "time"
 
 type LoginDTO struct {
-	Email    string `json:"email,omitempty"`
-	Username string `json:"username,omitempty"`
-	Passowrd string `json:"passowrd,omitempty"`
+	Email    string `json:"email,omitempty" validate:"required_without=Username,omitempty,email"`
+	Username string `json:"username,omitempty" validate:"required_without=Email"`
+	Password string `json:"password,omitempty" validate:"required"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token,omitempty" validate:"required"`
 }
 
 type TokenDTO struct {
-	User     *UserDTO   `json:"user,omitempty"`
-	Token    string     `json:"token,omitempty"`
-	ExpireAt *time.Time `json:"expire_at,omitempty"`
+	User            *UserDTO   `json:"user,omitempty"`
+	Token           string     `json:"token,omitempty"`
+	ExpireAt        *time.Time `json:"expire_at,omitempty"`
+	RefreshToken    string     `json:"refresh_token,omitempty"`
+	RefreshExpireAt *time.Time `json:"refresh_expire_at,omitempty"`
 }
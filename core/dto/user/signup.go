@@ -9,11 +9,11 @@ import (
 )
 
 type SignUpDTO struct {
-	Role     string `json:"role,omitempty"`
-	Email    string `json:"email,omitempty"`
-	Fullname string `json:"fullname,omitempty"`
-	Username string `json:"username,omitempty"`
-	Password string `json:"password,omitempty"`
+	Role     string `json:"role,omitempty" validate:"omitempty,max=64"`
+	Email    string `json:"email,omitempty" validate:"required,email,max=254"`
+	Fullname string `json:"fullname,omitempty" validate:"omitempty,max=128"`
+	Username string `json:"username,omitempty" validate:"required,alphanum,min=3,max=32"`
+	Password string `json:"password,omitempty" validate:"required,min=8,max=72"` // bcrypt only use the first 72 bytes
 }
 
 func (s SignUpDTO) ToUserEntity() (userEnt.User, error) {
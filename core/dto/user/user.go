@@ -1,11 +1,104 @@
 package user
 
+import (
+	"time"
+
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Pagination boundary for listing users
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
 type UserDTO struct {
-	Role     string `json:"role,omitempty"`
-	Email    string `json:"email,omitempty"`
-	UniqueId string `json:"unique_id,omitempty"`
-	Fullname string `json:"fullname,omitempty"`
-	Username string `json:"username,omitempty"`
-	Passowrd string `json:"passowrd,omitempty"`
-	Status   bool   `json:"status,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	UniqueId  string    `json:"unique_id,omitempty"`
+	Fullname  string    `json:"fullname,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	Status    bool      `json:"status,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FromUserEntity converts user entity to UserDTO, the password hash is never copied
+func FromUserEntity(user userEnt.User) UserDTO {
+	return UserDTO{
+		Role:      user.Role,
+		Email:     user.Email,
+		UniqueId:  user.UniqueId,
+		Fullname:  user.Fullname,
+		Username:  user.Username,
+		Status:    user.DeletedAt == nil,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+type ListUserDTO struct {
+	Page     int `form:"page" json:"page,omitempty" validate:"min=0"`
+	PageSize int `form:"page_size" json:"page_size,omitempty" validate:"min=0,max=100"`
+}
+
+// Normalize applies default page and clamp page size into allowed range
+func (l ListUserDTO) Normalize() ListUserDTO {
+	if l.Page < 1 {
+		l.Page = 1
+	}
+	if l.PageSize < 1 {
+		l.PageSize = DefaultPageSize
+	}
+	if l.PageSize > MaxPageSize {
+		l.PageSize = MaxPageSize
+	}
+	return l
+}
+
+func (l ListUserDTO) Offset() int {
+	return (l.Page - 1) * l.PageSize
+}
+
+type UserPageDTO struct {
+	Users    []UserDTO `json:"users"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	HasNext  bool      `json:"has_next"`
+}
+
+// UpdateUserDTO is a partial update, nil field is left unchanged
+type UpdateUserDTO struct {
+	Role     *string `json:"role,omitempty" validate:"omitempty,min=1,max=64"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Fullname *string `json:"fullname,omitempty" validate:"omitempty,max=128"`
+	Username *string `json:"username,omitempty" validate:"omitempty,alphanum,min=3,max=32"`
+	Password *string `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
+}
+
+// ApplyTo copies every non-nil field into the user entity, password is hashed before stored
+func (u UpdateUserDTO) ApplyTo(user userEnt.User) (userEnt.User, error) {
+	if u.Role != nil {
+		user.Role = *u.Role
+	}
+	if u.Email != nil {
+		user.Email = *u.Email
+	}
+	if u.Fullname != nil {
+		user.Fullname = *u.Fullname
+	}
+	if u.Username != nil {
+		user.Username = *u.Username
+	}
+	if u.Password != nil {
+		hashedPass, err := bcrypt.GenerateFromPassword([]byte(*u.Password), 10)
+		if err != nil {
+			return userEnt.User{}, err
+		}
+		user.Password = string(hashedPass)
+	}
+
+	user.UpdatedAt = time.Now().UTC()
+	return user, nil
 }
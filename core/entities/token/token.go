@@ -0,0 +1,18 @@
+package token
+
+import "time"
+
+// RefreshToken is the server side state of an opaque refresh token, the raw token
+// is never stored, only its hash is used as identifier.
+type RefreshToken struct {
+	Id        string
+	FamilyId  string
+	UniqueId  string
+	Used      bool
+	CreatedAt time.Time
+	ExpireAt  time.Time
+}
+
+func (t RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpireAt)
+}
@@ -0,0 +1,28 @@
+package user
+
+// Domain events of the user, published to other services through the outbox
+const (
+	EventUserSignedUp = "user.signed_up"
+	EventUserUpdated  = "user.updated"
+	EventUserDeleted  = "user.deleted"
+)
+
+// UserEvent is the payload of user domain events, password hash is never part of it
+type UserEvent struct {
+	UniqueId string `json:"unique_id"`
+	Role     string `json:"role,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Fullname string `json:"fullname,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// ToEvent returns event payload of the user
+func (user User) ToEvent() UserEvent {
+	return UserEvent{
+		UniqueId: user.UniqueId,
+		Role:     user.Role,
+		Email:    user.Email,
+		Fullname: user.Fullname,
+		Username: user.Username,
+	}
+}
@@ -0,0 +1,19 @@
+package user
+
+// Built-in roles of the user service, other roles can be defined through RBAC policy config
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Permissions checked by user service and its transport layer
+const (
+	PermissionUserRead      = "users:read"
+	PermissionUserWrite     = "users:write"
+	PermissionUserDelete    = "users:delete"
+	PermissionUserGrantRole = "users:grant-role"
+)
+
+// DefaultRBACPolicy is used when RBAC_POLICY is not configured, member has no
+// permission because access to their own account is always allowed.
+const DefaultRBACPolicy = RoleAdmin + "=*;" + RoleMember + "="
@@ -0,0 +1,23 @@
+package token
+
+import (
+	"context"
+	"errors"
+
+	tokenEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/token"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token already used")
+)
+
+type IRefreshTokenRepository interface {
+	// SaveRefreshToken stores token until its ExpireAt
+	SaveRefreshToken(ctx context.Context, token tokenEnt.RefreshToken) error
+	// ConsumeRefreshToken atomically marks token as used and return it, ErrRefreshTokenReused
+	// is returned together with the token when it has been consumed before.
+	ConsumeRefreshToken(ctx context.Context, familyId, id string) (tokenEnt.RefreshToken, error)
+	// RevokeFamily deletes every token that belong to the family
+	RevokeFamily(ctx context.Context, familyId string) error
+}
@@ -0,0 +1,75 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tokenEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/token"
+)
+
+// Ensure refreshTokenMemoryImpl implements IRefreshTokenRepository interface
+var _ IRefreshTokenRepository = (*refreshTokenMemoryImpl)(nil)
+
+// refreshTokenMemoryImpl implements the IRefreshTokenRepository interface in process memory,
+// it is meant for tests and local development with a single replica.
+type refreshTokenMemoryImpl struct {
+	mu       sync.Mutex
+	tokens   map[string]tokenEnt.RefreshToken
+	families map[string]map[string]struct{}
+}
+
+// NewRefreshTokenMemoryRepository creates a new in-memory instance of IRefreshTokenRepository
+func NewRefreshTokenMemoryRepository() IRefreshTokenRepository {
+	return &refreshTokenMemoryImpl{
+		tokens:   make(map[string]tokenEnt.RefreshToken),
+		families: make(map[string]map[string]struct{}),
+	}
+}
+
+// SaveRefreshToken stores a refresh token in memory
+func (r *refreshTokenMemoryImpl) SaveRefreshToken(ctx context.Context, token tokenEnt.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[token.Id] = token
+	if _, ok := r.families[token.FamilyId]; !ok {
+		r.families[token.FamilyId] = make(map[string]struct{})
+	}
+	r.families[token.FamilyId][token.Id] = struct{}{}
+
+	return nil
+}
+
+// ConsumeRefreshToken marks a refresh token as used
+func (r *refreshTokenMemoryImpl) ConsumeRefreshToken(ctx context.Context, familyId, id string) (tokenEnt.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.FamilyId != familyId || token.IsExpired(time.Now()) {
+		return tokenEnt.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+
+	if token.Used {
+		return token, ErrRefreshTokenReused
+	}
+
+	token.Used = true
+	r.tokens[id] = token
+
+	return token, nil
+}
+
+// RevokeFamily deletes every refresh token of a family
+func (r *refreshTokenMemoryImpl) RevokeFamily(ctx context.Context, familyId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id := range r.families[familyId] {
+		delete(r.tokens, id)
+	}
+	delete(r.families, familyId)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+	tokenEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/token"
+)
+
+// Ensure refreshTokenRedisImpl implements IRefreshTokenRepository interface
+var _ IRefreshTokenRepository = (*refreshTokenRedisImpl)(nil)
+
+// Every key of the same family share the {familyId} hash tag, so scripts below
+// stay in a single slot when running on Redis Cluster.
+const (
+	refreshTokenKeyFormat       = "%s:refresh_token:{%s}:%s"
+	refreshTokenFamilyKeyFormat = "%s:refresh_token_family:{%s}"
+)
+
+// saveRefreshTokenScript stores token hash and register it into its family set,
+// the family set lives as long as the newest token.
+var saveRefreshTokenScript = goRedis.NewScript(`
+redis.call('HSET', KEYS[1], 'id', ARGV[1], 'family_id', ARGV[2], 'unique_id', ARGV[3], 'used', '0', 'created_at', ARGV[4], 'expire_at', ARGV[5])
+redis.call('PEXPIREAT', KEYS[1], ARGV[5])
+redis.call('SADD', KEYS[2], ARGV[1])
+redis.call('PEXPIREAT', KEYS[2], ARGV[5])
+return 1
+`)
+
+// consumeRefreshTokenScript returns token fields with the previous "used" flag and mark it as used
+var consumeRefreshTokenScript = goRedis.NewScript(`
+local fields = redis.call('HMGET', KEYS[1], 'used', 'id', 'family_id', 'unique_id', 'created_at', 'expire_at')
+if not fields[2] then
+	return false
+end
+redis.call('HSET', KEYS[1], 'used', '1')
+return fields
+`)
+
+// revokeFamilyScript deletes every token registered in the family set, ARGV[1] is the token key prefix
+var revokeFamilyScript = goRedis.NewScript(`
+local ids = redis.call('SMEMBERS', KEYS[1])
+for _, id in ipairs(ids) do
+	redis.call('DEL', ARGV[1] .. id)
+end
+redis.call('DEL', KEYS[1])
+return #ids
+`)
+
+// refreshTokenRedisImpl implements the IRefreshTokenRepository interface for Redis
+type refreshTokenRedisImpl struct {
+	prefix string
+	client goRedis.UniversalClient
+}
+
+// NewRefreshTokenRedisRepository creates a new instance of IRefreshTokenRepository for Redis,
+// prefix is used to namespace the keys (e.g. application name).
+func NewRefreshTokenRedisRepository(client goRedis.UniversalClient, prefix string) IRefreshTokenRepository {
+	return &refreshTokenRedisImpl{
+		prefix: prefix,
+		client: client,
+	}
+}
+
+func (r *refreshTokenRedisImpl) tokenKey(familyId, id string) string {
+	return fmt.Sprintf(refreshTokenKeyFormat, r.prefix, familyId, id)
+}
+
+func (r *refreshTokenRedisImpl) familyKey(familyId string) string {
+	return fmt.Sprintf(refreshTokenFamilyKeyFormat, r.prefix, familyId)
+}
+
+// SaveRefreshToken stores a refresh token into Redis
+func (r *refreshTokenRedisImpl) SaveRefreshToken(ctx context.Context, token tokenEnt.RefreshToken) error {
+	keys := []string{r.tokenKey(token.FamilyId, token.Id), r.familyKey(token.FamilyId)}
+	args := []any{token.Id, token.FamilyId, token.UniqueId, token.CreatedAt.UnixMilli(), token.ExpireAt.UnixMilli()}
+	if err := saveRefreshTokenScript.Run(ctx, r.client, keys, args...).Err(); err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+// ConsumeRefreshToken marks a refresh token as used
+func (r *refreshTokenRedisImpl) ConsumeRefreshToken(ctx context.Context, familyId, id string) (tokenEnt.RefreshToken, error) {
+	keys := []string{r.tokenKey(familyId, id)}
+	res, err := consumeRefreshTokenScript.Run(ctx, r.client, keys).StringSlice()
+	if err != nil {
+		if errors.Is(err, goRedis.Nil) {
+			return tokenEnt.RefreshToken{}, ErrRefreshTokenNotFound
+		}
+		return tokenEnt.RefreshToken{}, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+
+	createdAt, _ := strconv.ParseInt(res[4], 10, 64)
+	expireAt, _ := strconv.ParseInt(res[5], 10, 64)
+	token := tokenEnt.RefreshToken{
+		Id:        res[1],
+		FamilyId:  res[2],
+		UniqueId:  res[3],
+		Used:      true,
+		CreatedAt: time.UnixMilli(createdAt).UTC(),
+		ExpireAt:  time.UnixMilli(expireAt).UTC(),
+	}
+
+	if res[0] == "1" {
+		return token, ErrRefreshTokenReused
+	}
+
+	return token, nil
+}
+
+// RevokeFamily deletes every refresh token of a family
+func (r *refreshTokenRedisImpl) RevokeFamily(ctx context.Context, familyId string) error {
+	keys := []string{r.familyKey(familyId)}
+	tokenKeyPrefix := r.tokenKey(familyId, "")
+	if err := revokeFamilyScript.Run(ctx, r.client, keys, tokenKeyPrefix).Err(); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package token_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tokenEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/token"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis/redistest"
+
+	. "github.com/wahyurudiyan/go-boilerplate/core/repositories/token"
+)
+
+func newRepositories(t *testing.T) map[string]IRefreshTokenRepository {
+	t.Helper()
+
+	repositories := map[string]IRefreshTokenRepository{
+		"memory": NewRefreshTokenMemoryRepository(),
+	}
+	for name, cfg := range redistest.Backends(t) {
+		client, err := redis.NewClient(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.Close() })
+		repositories["redis/"+name] = NewRefreshTokenRedisRepository(client, redistest.Prefix())
+	}
+	return repositories
+}
+
+func TestRefreshTokenRepository(t *testing.T) {
+	for name, repo := range newRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			first := tokenEnt.RefreshToken{Id: "hash-1", FamilyId: "family-1", UniqueId: "user-1", CreatedAt: now, ExpireAt: now.Add(time.Hour)}
+			second := tokenEnt.RefreshToken{Id: "hash-2", FamilyId: "family-1", UniqueId: "user-1", CreatedAt: now, ExpireAt: now.Add(time.Hour)}
+
+			if err := repo.SaveRefreshToken(ctx, first); err != nil {
+				t.Fatalf("SaveRefreshToken returned unexpected error: %v", err)
+			}
+			if err := repo.SaveRefreshToken(ctx, second); err != nil {
+				t.Fatalf("SaveRefreshToken returned unexpected error: %v", err)
+			}
+
+			got, err := repo.ConsumeRefreshToken(ctx, "family-1", "hash-1")
+			if err != nil {
+				t.Fatalf("ConsumeRefreshToken returned unexpected error: %v", err)
+			}
+			if got.UniqueId != "user-1" || !got.ExpireAt.Equal(first.ExpireAt) {
+				t.Errorf("Unexpected consumed token: %+v", got)
+			}
+
+			if _, err := repo.ConsumeRefreshToken(ctx, "family-1", "hash-1"); !errors.Is(err, ErrRefreshTokenReused) {
+				t.Errorf("Expected ErrRefreshTokenReused, got: %v", err)
+			}
+
+			if _, err := repo.ConsumeRefreshToken(ctx, "family-2", "hash-2"); !errors.Is(err, ErrRefreshTokenNotFound) {
+				t.Errorf("Expected ErrRefreshTokenNotFound for wrong family, got: %v", err)
+			}
+
+			if err := repo.RevokeFamily(ctx, "family-1"); err != nil {
+				t.Fatalf("RevokeFamily returned unexpected error: %v", err)
+			}
+
+			if _, err := repo.ConsumeRefreshToken(ctx, "family-1", "hash-2"); !errors.Is(err, ErrRefreshTokenNotFound) {
+				t.Errorf("Expected ErrRefreshTokenNotFound after revoke, got: %v", err)
+			}
+		})
+	}
+}
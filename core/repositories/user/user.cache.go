@@ -0,0 +1,442 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	sqlPkg "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+// Ensure userCacheImpl implements IUserRepository interface
+var _ IUserRepository = (*userCacheImpl)(nil)
+
+const (
+	defaultUserCacheTTL         = 5 * time.Minute
+	defaultUserCacheNegativeTTL = 30 * time.Second
+
+	// userCacheKeyFormat is <prefix>:user:<lookup>:<value>, every lookup of the user has its own key
+	userCacheKeyFormat = "%s:user:%s:%s"
+	// userCacheNegativeValue is cached when no user matches the lookup
+	userCacheNegativeValue = "-"
+)
+
+// Lookups of the cache, it is also the "lookup" attribute of hit and miss metrics
+const (
+	lookupId       = "id"
+	lookupEmail    = "email"
+	lookupUsername = "username"
+	lookupUniqueId = "unique_id"
+)
+
+type UserCacheConfig struct {
+	// Prefix namespaces the keys (e.g. application name)
+	Prefix string
+	// TTL of cached user, default is 5 minutes
+	TTL time.Duration
+	// NegativeTTL of lookup without user, default is 30 seconds. It bounds how long a user
+	// created by SaveUsers or UpsertUser stays invisible to id lookup, their id is unknown on insert.
+	NegativeTTL time.Duration
+}
+
+// userCacheImpl is a cache-aside decorator of IUserRepository. Reads inside transaction
+// bypass the cache so uncommitted data is never cached, and cache is invalidated after
+// the transaction of the write is committed. Redis failure falls back to the next repository.
+// Password hash is never cached, so users returned outside transaction have empty Password.
+type userCacheImpl struct {
+	next   IUserRepository
+	client goRedis.UniversalClient
+	cfg    UserCacheConfig
+	group  singleflight.Group
+
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+// NewUserCacheRepository returns IUserRepository that caches lookups of next in Redis
+func NewUserCacheRepository(next IUserRepository, client goRedis.UniversalClient, cfg UserCacheConfig) (IUserRepository, error) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultUserCacheTTL
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = defaultUserCacheNegativeTTL
+	}
+
+	meter := otel.Meter("github.com/wahyurudiyan/go-boilerplate/core/repositories/user")
+	hits, err := meter.Int64Counter("user.cache.hits", metric.WithDescription("Number of user lookups served by cache"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache hit counter: %w", err)
+	}
+	misses, err := meter.Int64Counter("user.cache.misses", metric.WithDescription("Number of user lookups loaded from repository"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache miss counter: %w", err)
+	}
+
+	return &userCacheImpl{
+		next:   next,
+		client: client,
+		cfg:    cfg,
+		hits:   hits,
+		misses: misses,
+	}, nil
+}
+
+// cachedUser is the cached projection of userEnt.User, it has no password hash so the hash
+// is not readable by other clients of the shared Redis. Field names are the same as the
+// entity, so entry cached by previous release is still decoded.
+type cachedUser struct {
+	Id        int64
+	Role      string
+	Email     string
+	UniqueId  string
+	Fullname  string
+	Username  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func newCachedUser(user userEnt.User) cachedUser {
+	return cachedUser{
+		Id:        user.Id,
+		Role:      user.Role,
+		Email:     user.Email,
+		UniqueId:  user.UniqueId,
+		Fullname:  user.Fullname,
+		Username:  user.Username,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		DeletedAt: user.DeletedAt,
+	}
+}
+
+func (c cachedUser) toEntity() userEnt.User {
+	return userEnt.User{
+		Id:        c.Id,
+		Role:      c.Role,
+		Email:     c.Email,
+		UniqueId:  c.UniqueId,
+		Fullname:  c.Fullname,
+		Username:  c.Username,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		DeletedAt: c.DeletedAt,
+	}
+}
+
+func (r *userCacheImpl) key(lookup string, value any) string {
+	return fmt.Sprintf(userCacheKeyFormat, r.cfg.Prefix, lookup, value)
+}
+
+// keysOf returns every lookup key of user, lookup with empty value is skipped
+func (r *userCacheImpl) keysOf(user userEnt.User) []string {
+	var keys []string
+	if user.Id != 0 {
+		keys = append(keys, r.key(lookupId, user.Id))
+	}
+	for lookup, value := range map[string]string{lookupEmail: user.Email, lookupUsername: user.Username, lookupUniqueId: user.UniqueId} {
+		if value != "" {
+			keys = append(keys, r.key(lookup, value))
+		}
+	}
+	return keys
+}
+
+// SaveUser clears negative cache of the new user, including the lookup of its generated id
+func (r *userCacheImpl) SaveUser(ctx context.Context, user userEnt.User) (int64, error) {
+	id, err := r.next.SaveUser(ctx, user)
+	if err != nil {
+		return 0, err
+	}
+	user.Id = id
+	r.invalidate(ctx, user)
+	return id, nil
+}
+
+// SaveUsers clears negative cache of the new users
+func (r *userCacheImpl) SaveUsers(ctx context.Context, users []userEnt.User) error {
+	if err := r.next.SaveUsers(ctx, users); err != nil {
+		return err
+	}
+	r.invalidate(ctx, users...)
+	return nil
+}
+
+// UpsertUser invalidates lookups of the stored user and of the new one, like UpdateUser
+func (r *userCacheImpl) UpsertUser(ctx context.Context, user userEnt.User) error {
+	previous, err := r.next.RetrieveUserByUniqueId(ctx, user.UniqueId)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+
+	if err := r.next.UpsertUser(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, previous, user)
+	return nil
+}
+
+// UpdateUser invalidates both previous and new lookups, e.g. the old email. Empty Password
+// of user read from the cache keeps the stored password hash.
+func (r *userCacheImpl) UpdateUser(ctx context.Context, user userEnt.User) error {
+	previous, err := r.next.RetrieveUserById(ctx, user.Id)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+	if user.Password == "" {
+		user.Password = previous.Password
+	}
+
+	if err := r.next.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, previous, user)
+	return nil
+}
+
+func (r *userCacheImpl) DeleteUserById(ctx context.Context, id int64) error {
+	return deleteCached(ctx, r, id, r.next.RetrieveUserById, r.next.DeleteUserById)
+}
+
+func (r *userCacheImpl) DeleteUserByEmail(ctx context.Context, email string) error {
+	return deleteCached(ctx, r, email, r.next.RetrieveUserByEmail, r.next.DeleteUserByEmail)
+}
+
+func (r *userCacheImpl) DeleteUserByUniqueId(ctx context.Context, uniqueId string) error {
+	return deleteCached(ctx, r, uniqueId, r.next.RetrieveUserByUniqueId, r.next.DeleteUserByUniqueId)
+}
+
+// RetrieveAllUser is not cached, pages change on every write
+func (r *userCacheImpl) RetrieveAllUser(ctx context.Context, offset, limit int) ([]userEnt.User, error) {
+	return r.next.RetrieveAllUser(ctx, offset, limit)
+}
+
+func (r *userCacheImpl) RetrieveUserById(ctx context.Context, id int64) (userEnt.User, error) {
+	return retrieveCached(ctx, r, lookupId, id, r.next.RetrieveUserById)
+}
+
+func (r *userCacheImpl) RetrieveUserByIds(ctx context.Context, ids []int64) ([]userEnt.User, error) {
+	return retrieveManyCached(ctx, r, lookupId, ids, func(user userEnt.User) int64 { return user.Id }, r.next.RetrieveUserByIds)
+}
+
+func (r *userCacheImpl) RetrieveUserByEmail(ctx context.Context, email string) (userEnt.User, error) {
+	return retrieveCached(ctx, r, lookupEmail, email, r.next.RetrieveUserByEmail)
+}
+
+func (r *userCacheImpl) RetrieveUserByEmails(ctx context.Context, emails []string) ([]userEnt.User, error) {
+	return retrieveManyCached(ctx, r, lookupEmail, emails, func(user userEnt.User) string { return user.Email }, r.next.RetrieveUserByEmails)
+}
+
+func (r *userCacheImpl) RetrieveUserByUsername(ctx context.Context, username string) (userEnt.User, error) {
+	return retrieveCached(ctx, r, lookupUsername, username, r.next.RetrieveUserByUsername)
+}
+
+func (r *userCacheImpl) RetrieveUserByUniqueId(ctx context.Context, uniqueId string) (userEnt.User, error) {
+	return retrieveCached(ctx, r, lookupUniqueId, uniqueId, r.next.RetrieveUserByUniqueId)
+}
+
+func (r *userCacheImpl) RetrieveUserByUniqueIds(ctx context.Context, uniqueIds []string) ([]userEnt.User, error) {
+	return retrieveManyCached(ctx, r, lookupUniqueId, uniqueIds, func(user userEnt.User) string { return user.UniqueId }, r.next.RetrieveUserByUniqueIds)
+}
+
+// RetrievePasswordByUniqueId is not cached, password hash is read from next repository only
+func (r *userCacheImpl) RetrievePasswordByUniqueId(ctx context.Context, uniqueId string) (string, error) {
+	return r.next.RetrievePasswordByUniqueId(ctx, uniqueId)
+}
+
+// deleteCached retrieves the user first, so every lookup of it can be invalidated
+func deleteCached[T any](ctx context.Context, r *userCacheImpl, value T, retrieve func(context.Context, T) (userEnt.User, error), remove func(context.Context, T) error) error {
+	user, err := retrieve(ctx, value)
+	if err != nil {
+		return err
+	}
+
+	if err := remove(ctx, value); err != nil {
+		return err
+	}
+	r.invalidate(ctx, user)
+	return nil
+}
+
+// retrieveCached returns user of a single lookup, concurrent misses of the same key are
+// collapsed into one load
+func retrieveCached[T comparable](ctx context.Context, r *userCacheImpl, lookup string, value T, load func(context.Context, T) (userEnt.User, error)) (userEnt.User, error) {
+	if sqlPkg.InTx(ctx) {
+		return load(ctx, value)
+	}
+
+	key := r.key(lookup, value)
+	cached := r.get(ctx, lookup, []string{key})
+	if user, ok := cached[key]; ok {
+		r.hits.Add(ctx, 1, metric.WithAttributes(attribute.String("lookup", lookup)))
+		if user == nil {
+			return userEnt.User{}, fmt.Errorf("%w: %s %v", ErrUserNotFound, lookup, value)
+		}
+		return *user, nil
+	}
+	r.misses.Add(ctx, 1, metric.WithAttributes(attribute.String("lookup", lookup)))
+
+	loaded, err, _ := r.group.Do(key, func() (any, error) {
+		user, err := load(ctx, value)
+		switch {
+		case err == nil:
+			r.set(ctx, nil, user)
+			// Loaded user is returned like cached one, so the result does not depend on hit or miss
+			user = newCachedUser(user).toEntity()
+		case errors.Is(err, ErrUserNotFound):
+			r.set(ctx, []string{key})
+		}
+		return user, err
+	})
+	return loaded.(userEnt.User), err
+}
+
+// retrieveManyCached returns users of a batch lookup in the order of values, only the
+// missing values are loaded
+func retrieveManyCached[T comparable](ctx context.Context, r *userCacheImpl, lookup string, values []T, valueOf func(userEnt.User) T, load func(context.Context, []T) ([]userEnt.User, error)) ([]userEnt.User, error) {
+	if sqlPkg.InTx(ctx) || len(values) == 0 {
+		return load(ctx, values)
+	}
+
+	keys := make([]string, len(values))
+	for i, value := range values {
+		keys[i] = r.key(lookup, value)
+	}
+
+	cached := r.get(ctx, lookup, keys)
+	found := make(map[T]userEnt.User, len(values))
+	var missing []T
+	for i, value := range values {
+		user, ok := cached[keys[i]]
+		switch {
+		case !ok:
+			if _, seen := found[value]; !seen && !slices.Contains(missing, value) {
+				missing = append(missing, value)
+			}
+		case user != nil:
+			found[value] = *user
+		}
+	}
+	r.hits.Add(ctx, int64(len(cached)), metric.WithAttributes(attribute.String("lookup", lookup)))
+
+	if len(missing) > 0 {
+		r.misses.Add(ctx, int64(len(missing)), metric.WithAttributes(attribute.String("lookup", lookup)))
+		loaded, err := load(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range loaded {
+			found[valueOf(user)] = newCachedUser(user).toEntity()
+		}
+		var negativeKeys []string
+		for _, value := range missing {
+			if _, ok := found[value]; !ok {
+				negativeKeys = append(negativeKeys, r.key(lookup, value))
+			}
+		}
+		r.set(ctx, negativeKeys, loaded...)
+	}
+
+	users := make([]userEnt.User, 0, len(found))
+	for _, value := range values {
+		if user, ok := found[value]; ok {
+			users = append(users, user)
+			delete(found, value)
+		}
+	}
+	return users, nil
+}
+
+// get returns cached entries of keys, nil user is negative entry and missing key is
+// cache miss. Keys are read with pipelined GET instead of MGET, so they may live in
+// different slots of Redis Cluster.
+func (r *userCacheImpl) get(ctx context.Context, lookup string, keys []string) map[string]*userEnt.User {
+	cmds := make([]*goRedis.StringCmd, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe goRedis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, goRedis.Nil) {
+		slog.WarnContext(ctx, "[USER CACHE] failed to read cache", "lookup", lookup, "error", err)
+	}
+
+	entries := make(map[string]*userEnt.User, len(keys))
+	for i, cmd := range cmds {
+		value, err := cmd.Result()
+		if err != nil {
+			continue
+		}
+		if value == userCacheNegativeValue {
+			entries[keys[i]] = nil
+			continue
+		}
+
+		var cached cachedUser
+		if err := json.Unmarshal([]byte(value), &cached); err != nil {
+			continue
+		}
+		user := cached.toEntity()
+		entries[keys[i]] = &user
+	}
+	return entries
+}
+
+// set caches every lookup of users and negative entry of negativeKeys
+func (r *userCacheImpl) set(ctx context.Context, negativeKeys []string, users ...userEnt.User) {
+	_, err := r.client.Pipelined(ctx, func(pipe goRedis.Pipeliner) error {
+		for _, key := range negativeKeys {
+			pipe.Set(ctx, key, userCacheNegativeValue, r.cfg.NegativeTTL)
+		}
+		for _, user := range users {
+			value, err := json.Marshal(newCachedUser(user))
+			if err != nil {
+				return err
+			}
+			for _, key := range r.keysOf(user) {
+				pipe.Set(ctx, key, value, r.cfg.TTL)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "[USER CACHE] failed to write cache", "error", err)
+	}
+}
+
+// invalidate deletes every lookup of users once the transaction of ctx is committed
+func (r *userCacheImpl) invalidate(ctx context.Context, users ...userEnt.User) {
+	var keys []string
+	for _, user := range users {
+		keys = append(keys, r.keysOf(user)...)
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	sqlPkg.AfterCommit(ctx, func() {
+		ctx := context.WithoutCancel(ctx)
+		_, err := r.client.Pipelined(ctx, func(pipe goRedis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Del(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "[USER CACHE] failed to invalidate cache", "keys", keys, "error", err)
+		}
+	})
+}
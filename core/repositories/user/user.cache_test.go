@@ -0,0 +1,308 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goRedis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+)
+
+// countingRepository is an in-memory IUserRepository that counts the lookups reaching it
+type countingRepository struct {
+	IUserRepository
+
+	mu      sync.Mutex
+	users   map[int64]userEnt.User
+	loads   atomic.Int64
+	release chan struct{}
+}
+
+func newCountingRepository(users ...userEnt.User) *countingRepository {
+	repo := &countingRepository{users: make(map[int64]userEnt.User)}
+	for _, user := range users {
+		repo.users[user.Id] = user
+	}
+	return repo
+}
+
+func (r *countingRepository) find(match func(userEnt.User) bool) (userEnt.User, error) {
+	r.loads.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+	return userEnt.User{}, fmt.Errorf("%w: fake", ErrUserNotFound)
+}
+
+func (r *countingRepository) SaveUser(ctx context.Context, user userEnt.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.Id] = user
+	return user.Id, nil
+}
+
+func (r *countingRepository) UpdateUser(ctx context.Context, user userEnt.User) error {
+	_, err := r.SaveUser(ctx, user)
+	return err
+}
+
+func (r *countingRepository) DeleteUserByUniqueId(ctx context.Context, uniqueId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, user := range r.users {
+		if user.UniqueId == uniqueId {
+			delete(r.users, id)
+		}
+	}
+	return nil
+}
+
+func (r *countingRepository) RetrieveUserById(ctx context.Context, id int64) (userEnt.User, error) {
+	return r.find(func(user userEnt.User) bool { return user.Id == id })
+}
+
+func (r *countingRepository) RetrieveUserByEmail(ctx context.Context, email string) (userEnt.User, error) {
+	return r.find(func(user userEnt.User) bool { return user.Email == email })
+}
+
+func (r *countingRepository) RetrieveUserByUniqueId(ctx context.Context, uniqueId string) (userEnt.User, error) {
+	return r.find(func(user userEnt.User) bool { return user.UniqueId == uniqueId })
+}
+
+func (r *countingRepository) RetrievePasswordByUniqueId(ctx context.Context, uniqueId string) (string, error) {
+	user, err := r.find(func(user userEnt.User) bool { return user.UniqueId == uniqueId })
+	return user.Password, err
+}
+
+func (r *countingRepository) RetrieveUserByUniqueIds(ctx context.Context, uniqueIds []string) ([]userEnt.User, error) {
+	r.loads.Add(int64(len(uniqueIds)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []userEnt.User
+	for _, uniqueId := range uniqueIds {
+		for _, user := range r.users {
+			if user.UniqueId == uniqueId {
+				users = append(users, user)
+			}
+		}
+	}
+	return users, nil
+}
+
+func newCacheRepository(t *testing.T, next IUserRepository) IUserRepository {
+	t.Helper()
+	return newCacheRepositoryOf(t, next, miniredis.RunT(t))
+}
+
+func newCacheRepositoryOf(t *testing.T, next IUserRepository, server *miniredis.Miniredis) IUserRepository {
+	t.Helper()
+
+	client := goRedis.NewClient(&goRedis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	repo, err := NewUserCacheRepository(next, client, UserCacheConfig{Prefix: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+var (
+	jane = userEnt.User{Id: 1, Email: "jane@example.com", Username: "jane", UniqueId: "uid-1"}
+	john = userEnt.User{Id: 2, Email: "john@example.com", Username: "john", UniqueId: "uid-2"}
+)
+
+func TestUserCacheRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hit after miss caches every lookup", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		t.Cleanup(func() { otel.SetMeterProvider(sdkmetric.NewMeterProvider()) })
+
+		next := newCountingRepository(jane)
+		repo := newCacheRepository(t, next)
+
+		for range 2 {
+			if user, err := repo.RetrieveUserByUniqueId(ctx, jane.UniqueId); err != nil || user.Email != jane.Email {
+				t.Fatalf("Expected jane, got: %+v, %v", user, err)
+			}
+		}
+		if user, err := repo.RetrieveUserByEmail(ctx, jane.Email); err != nil || user.UniqueId != jane.UniqueId {
+			t.Fatalf("Expected jane, got: %+v, %v", user, err)
+		}
+		if loads := next.loads.Load(); loads != 1 {
+			t.Errorf("Expected 1 load, got: %d", loads)
+		}
+
+		var metrics metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &metrics); err != nil {
+			t.Fatal(err)
+		}
+		counts := map[string]int64{}
+		for _, scope := range metrics.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					counts[m.Name] += point.Value
+				}
+			}
+		}
+		if counts["user.cache.hits"] != 2 || counts["user.cache.misses"] != 1 {
+			t.Errorf("Expected 2 hits and 1 miss, got: %v", counts)
+		}
+	})
+
+	t.Run("negative cache is cleared on save", func(t *testing.T) {
+		next := newCountingRepository()
+		repo := newCacheRepository(t, next)
+
+		for range 2 {
+			if _, err := repo.RetrieveUserByUniqueId(ctx, jane.UniqueId); !errors.Is(err, ErrUserNotFound) {
+				t.Fatalf("Expected ErrUserNotFound, got: %v", err)
+			}
+		}
+		if loads := next.loads.Load(); loads != 1 {
+			t.Errorf("Expected missing user to be loaded once, got: %d", loads)
+		}
+
+		if _, err := repo.SaveUser(ctx, jane); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.RetrieveUserByUniqueId(ctx, jane.UniqueId); err != nil {
+			t.Errorf("Expected saved user, got: %v", err)
+		}
+	})
+
+	t.Run("update invalidates previous lookups", func(t *testing.T) {
+		repo := newCacheRepository(t, newCountingRepository(jane))
+		if _, err := repo.RetrieveUserByEmail(ctx, jane.Email); err != nil {
+			t.Fatal(err)
+		}
+
+		updated := jane
+		updated.Email = "jane.doe@example.com"
+		if err := repo.UpdateUser(ctx, updated); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repo.RetrieveUserByEmail(ctx, jane.Email); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("Expected old email to be invalidated, got: %v", err)
+		}
+		if user, err := repo.RetrieveUserByUniqueId(ctx, jane.UniqueId); err != nil || user.Email != updated.Email {
+			t.Errorf("Expected updated user, got: %+v, %v", user, err)
+		}
+	})
+
+	t.Run("delete invalidates every lookup", func(t *testing.T) {
+		repo := newCacheRepository(t, newCountingRepository(jane))
+		if _, err := repo.RetrieveUserById(ctx, jane.Id); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.DeleteUserByUniqueId(ctx, jane.UniqueId); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.RetrieveUserById(ctx, jane.Id); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("Expected deleted user to be invalidated, got: %v", err)
+		}
+	})
+
+	t.Run("batch loads only missing values", func(t *testing.T) {
+		next := newCountingRepository(jane, john)
+		repo := newCacheRepository(t, next)
+		if _, err := repo.RetrieveUserByUniqueId(ctx, john.UniqueId); err != nil {
+			t.Fatal(err)
+		}
+
+		users, err := repo.RetrieveUserByUniqueIds(ctx, []string{jane.UniqueId, "uid-404", john.UniqueId})
+		if err != nil || len(users) != 2 || users[0].Id != jane.Id || users[1].Id != john.Id {
+			t.Fatalf("Expected jane and john in order, got: %+v, %v", users, err)
+		}
+		if loads := next.loads.Load(); loads != 3 {
+			t.Errorf("Expected jane and missing user to be loaded, got: %d loads", loads)
+		}
+
+		if _, err := repo.RetrieveUserByUniqueIds(ctx, []string{jane.UniqueId, "uid-404", john.UniqueId}); err != nil {
+			t.Fatal(err)
+		}
+		if loads := next.loads.Load(); loads != 3 {
+			t.Errorf("Expected second batch to be served by cache, got: %d loads", loads)
+		}
+	})
+
+	t.Run("password hash is not cached", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		withPassword := jane
+		withPassword.Password = "hash"
+		next := newCountingRepository(withPassword)
+		repo := newCacheRepositoryOf(t, next, server)
+
+		for range 2 {
+			if user, err := repo.RetrieveUserByEmail(ctx, jane.Email); err != nil || user.Password != "" {
+				t.Fatalf("Expected jane without password, got: %+v, %v", user, err)
+			}
+		}
+		for _, key := range server.Keys() {
+			if value, _ := server.Get(key); strings.Contains(value, "hash") {
+				t.Errorf("Expected no password hash in %s, got: %s", key, value)
+			}
+		}
+
+		if password, err := repo.RetrievePasswordByUniqueId(ctx, jane.UniqueId); err != nil || password != "hash" {
+			t.Errorf("Expected password hash from repository, got: %q, %v", password, err)
+		}
+
+		cached, _ := repo.RetrieveUserByEmail(ctx, jane.Email)
+		cached.Fullname = "Jane Doe"
+		if err := repo.UpdateUser(ctx, cached); err != nil {
+			t.Fatal(err)
+		}
+		if password, _ := next.RetrievePasswordByUniqueId(ctx, jane.UniqueId); password != "hash" {
+			t.Errorf("Expected update of cached user to keep password hash, got: %q", password)
+		}
+	})
+
+	t.Run("concurrent misses are collapsed", func(t *testing.T) {
+		next := newCountingRepository(jane)
+		next.release = make(chan struct{})
+		repo := newCacheRepository(t, next)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := repo.RetrieveUserByUniqueId(ctx, jane.UniqueId); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(next.release)
+		wg.Wait()
+
+		if loads := next.loads.Load(); loads != 1 {
+			t.Errorf("Expected concurrent misses to load once, got: %d", loads)
+		}
+	})
+}
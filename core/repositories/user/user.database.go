@@ -2,12 +2,12 @@ package user
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	sqlPkg "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
 )
 
 // Ensure userRepositoryImpl implements IUserRepository interface
@@ -15,30 +15,51 @@ var _ IUserRepository = (*userRepositoryImpl)(nil)
 
 // userRepositoryImpl implements the IUserRepository interface
 type userRepositoryImpl struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	dialect sqlPkg.IDialect
 }
 
-// NewIUserRepository creates a new instance of IUserRepository
-func NewUserSQLRepository(db *sqlx.DB) IUserRepository {
-	return &userRepositoryImpl{
-		db: db,
+// NewIUserRepository creates a new instance of IUserRepository, queries are written with
+// ? bindvar and rebound to the dialect of the db driver
+func NewUserSQLRepository(db *sqlx.DB) (IUserRepository, error) {
+	dialect, err := sqlPkg.DialectOf(db.DriverName())
+	if err != nil {
+		return nil, err
 	}
+
+	return &userRepositoryImpl{
+		db:      db,
+		dialect: dialect,
+	}, nil
 }
 
-// SaveUser inserts a single user into the database
-func (r *userRepositoryImpl) SaveUser(ctx context.Context, user userEnt.User) error {
-	query := `
+// executor returns the transaction of ctx, so repository joins the unit of work of the caller
+func (r *userRepositoryImpl) executor(ctx context.Context) sqlPkg.IExecutor {
+	return sqlPkg.Executor(ctx, r.db)
+}
+
+// SaveUser inserts a single user into the database and returns its generated id
+func (r *userRepositoryImpl) SaveUser(ctx context.Context, user userEnt.User) (int64, error) {
+	user.CreatedAt, user.UpdatedAt = r.dialect.Timestamp(user.CreatedAt), r.dialect.Timestamp(user.UpdatedAt)
+	query, args, err := sqlx.Named(`
 		INSERT INTO users (
 			role, email, unique_id, fullname, username, password, created_at, updated_at
 		) VALUES (
 			:role, :email, :unique_id, :fullname, :username, :password, :created_at, :updated_at
 		)
-	`
-	_, err := r.db.NamedExecContext(ctx, query, user)
+	`, user)
 	if err != nil {
-		return fmt.Errorf("failed to save user: %w", err)
+		return 0, fmt.Errorf("failed to bind user: %w", err)
 	}
-	return nil
+
+	id, err := r.dialect.InsertID(ctx, r.executor(ctx), query, args...)
+	if err != nil {
+		if sqlPkg.IsUniqueViolation(err) {
+			return 0, ErrUserAlreadyExists.Wrap(err)
+		}
+		return 0, fmt.Errorf("failed to save user: %w", err)
+	}
+	return id, nil
 }
 
 // SaveUsers inserts multiple users into the database
@@ -47,6 +68,11 @@ func (r *userRepositoryImpl) SaveUsers(ctx context.Context, users []userEnt.User
 		return nil
 	}
 
+	rows := make([]userEnt.User, len(users))
+	for i, user := range users {
+		user.CreatedAt, user.UpdatedAt = r.dialect.Timestamp(user.CreatedAt), r.dialect.Timestamp(user.UpdatedAt)
+		rows[i] = user
+	}
 	query := `
 		INSERT INTO users (
 			role, email, unique_id, fullname, username, password, created_at, updated_at
@@ -54,15 +80,38 @@ func (r *userRepositoryImpl) SaveUsers(ctx context.Context, users []userEnt.User
 			:role, :email, :unique_id, :fullname, :username, :password, :created_at, :updated_at
 		)
 	`
-	_, err := r.db.NamedExecContext(ctx, query, users)
+	_, err := r.executor(ctx).NamedExecContext(ctx, query, rows)
 	if err != nil {
+		if sqlPkg.IsUniqueViolation(err) {
+			return ErrUserAlreadyExists.Wrap(err)
+		}
 		return fmt.Errorf("failed to save users: %w", err)
 	}
 	return nil
 }
 
+// UpsertUser inserts the user or updates the user with the same unique ID, e.g. user
+// synchronized from identity provider. Soft deleted user stays deleted.
+func (r *userRepositoryImpl) UpsertUser(ctx context.Context, user userEnt.User) error {
+	user.CreatedAt, user.UpdatedAt = r.dialect.Timestamp(user.CreatedAt), r.dialect.Timestamp(user.UpdatedAt)
+	query := r.dialect.Upsert("users",
+		[]string{"role", "email", "unique_id", "fullname", "username", "password", "created_at", "updated_at"},
+		[]string{"unique_id"},
+		[]string{"role", "email", "fullname", "username", "password", "updated_at"},
+	)
+	_, err := r.executor(ctx).NamedExecContext(ctx, query, user)
+	if err != nil {
+		if sqlPkg.IsUniqueViolation(err) {
+			return ErrUserAlreadyExists.Wrap(err)
+		}
+		return fmt.Errorf("failed to upsert user: %w", err)
+	}
+	return nil
+}
+
 // UpdateUser updates an existing user in the database
 func (r *userRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.User) error {
+	user.UpdatedAt = r.dialect.Timestamp(user.UpdatedAt)
 	query := `
 		UPDATE users SET
 			role = :role,
@@ -72,10 +121,13 @@ func (r *userRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.User)
 			username = :username,
 			password = :password,
 			updated_at = :updated_at
-		WHERE id = :id
+		WHERE id = :id AND deleted_at IS NULL
 	`
-	result, err := r.db.NamedExecContext(ctx, query, user)
+	result, err := r.executor(ctx).NamedExecContext(ctx, query, user)
 	if err != nil {
+		if sqlPkg.IsUniqueViolation(err) {
+			return ErrUserAlreadyExists.Wrap(err)
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
@@ -85,7 +137,7 @@ func (r *userRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.User)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user with id %d not found", user.Id)
+		return fmt.Errorf("%w: id %d", ErrUserNotFound, user.Id)
 	}
 
 	return nil
@@ -93,8 +145,8 @@ func (r *userRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.User)
 
 // DeleteUserById deletes a user by ID
 func (r *userRepositoryImpl) DeleteUserById(ctx context.Context, id int64) error {
-	query := `UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := r.dialect.Rebind(`UPDATE users SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`)
+	result, err := r.executor(ctx).ExecContext(ctx, query, r.dialect.Timestamp(time.Now()), id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user by id: %w", err)
 	}
@@ -105,7 +157,7 @@ func (r *userRepositoryImpl) DeleteUserById(ctx context.Context, id int64) error
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user with id %d not found", id)
+		return fmt.Errorf("%w: id %d", ErrUserNotFound, id)
 	}
 
 	return nil
@@ -113,8 +165,8 @@ func (r *userRepositoryImpl) DeleteUserById(ctx context.Context, id int64) error
 
 // DeleteUserByEmail deletes a user by email
 func (r *userRepositoryImpl) DeleteUserByEmail(ctx context.Context, email string) error {
-	query := `UPDATE users SET deleted_at = NOW() WHERE email = $1 AND deleted_at IS NULL`
-	result, err := r.db.ExecContext(ctx, query, email)
+	query := r.dialect.Rebind(`UPDATE users SET deleted_at = ? WHERE email = ? AND deleted_at IS NULL`)
+	result, err := r.executor(ctx).ExecContext(ctx, query, r.dialect.Timestamp(time.Now()), email)
 	if err != nil {
 		return fmt.Errorf("failed to delete user by email: %w", err)
 	}
@@ -125,7 +177,7 @@ func (r *userRepositoryImpl) DeleteUserByEmail(ctx context.Context, email string
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user with email %s not found", email)
+		return fmt.Errorf("%w: email %s", ErrUserNotFound, email)
 	}
 
 	return nil
@@ -133,8 +185,8 @@ func (r *userRepositoryImpl) DeleteUserByEmail(ctx context.Context, email string
 
 // DeleteUserByUniqueId deletes a user by unique ID
 func (r *userRepositoryImpl) DeleteUserByUniqueId(ctx context.Context, uniqueId string) error {
-	query := `UPDATE users SET deleted_at = NOW() WHERE unique_id = $1 AND deleted_at IS NULL`
-	result, err := r.db.ExecContext(ctx, query, uniqueId)
+	query := r.dialect.Rebind(`UPDATE users SET deleted_at = ? WHERE unique_id = ? AND deleted_at IS NULL`)
+	result, err := r.executor(ctx).ExecContext(ctx, query, r.dialect.Timestamp(time.Now()), uniqueId)
 	if err != nil {
 		return fmt.Errorf("failed to delete user by unique id: %w", err)
 	}
@@ -145,7 +197,7 @@ func (r *userRepositoryImpl) DeleteUserByUniqueId(ctx context.Context, uniqueId
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user with unique id %s not found", uniqueId)
+		return fmt.Errorf("%w: unique id %s", ErrUserNotFound, uniqueId)
 	}
 
 	return nil
@@ -154,14 +206,14 @@ func (r *userRepositoryImpl) DeleteUserByUniqueId(ctx context.Context, uniqueId
 // RetrieveAllUser retrieves all users with pagination
 func (r *userRepositoryImpl) RetrieveAllUser(ctx context.Context, offset, limit int) ([]userEnt.User, error) {
 	var users []userEnt.User
-	query := `
+	query := r.dialect.Rebind(`
 		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
 		FROM users
 		WHERE deleted_at IS NULL
 		ORDER BY id
-		LIMIT $1 OFFSET $2
-	`
-	err := r.db.SelectContext(ctx, &users, query, limit, offset)
+		LIMIT ? OFFSET ?
+	`)
+	err := r.executor(ctx).SelectContext(ctx, &users, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve all users: %w", err)
 	}
@@ -171,15 +223,15 @@ func (r *userRepositoryImpl) RetrieveAllUser(ctx context.Context, offset, limit
 // RetrieveUserById retrieves a user by ID
 func (r *userRepositoryImpl) RetrieveUserById(ctx context.Context, id int64) (userEnt.User, error) {
 	var user userEnt.User
-	query := `
+	query := r.dialect.Rebind(`
 		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
 		FROM users
-		WHERE id = $1 AND deleted_at IS NULL
-	`
-	err := r.db.GetContext(ctx, &user, query, id)
+		WHERE id = ? AND deleted_at IS NULL
+	`)
+	err := r.executor(ctx).GetContext(ctx, &user, query, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return userEnt.User{}, fmt.Errorf("user with id %d not found", id)
+		if sqlPkg.IsNoRows(err) {
+			return userEnt.User{}, fmt.Errorf("%w: id %d", ErrUserNotFound, id)
 		}
 		return userEnt.User{}, fmt.Errorf("failed to retrieve user by id: %w", err)
 	}
@@ -192,26 +244,7 @@ func (r *userRepositoryImpl) RetrieveUserByIds(ctx context.Context, ids []int64)
 		return []userEnt.User{}, nil
 	}
 
-	// Convert ids to interface{} slice for query args
-	args := make([]interface{}, len(ids))
-	for i, id := range ids {
-		args[i] = id
-	}
-
-	// Create placeholders like $1, $2, $3...
-	placeholders := make([]string, len(ids))
-	for i := range ids {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-	}
-
-	query := fmt.Sprintf(`
-		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
-		FROM users
-		WHERE id IN (%s) AND deleted_at IS NULL
-	`, strings.Join(placeholders, ", "))
-
-	var users []userEnt.User
-	err := r.db.SelectContext(ctx, &users, query, args...)
+	users, err := r.retrieveUsersIn(ctx, "id", ids)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve users by ids: %w", err)
 	}
@@ -221,15 +254,15 @@ func (r *userRepositoryImpl) RetrieveUserByIds(ctx context.Context, ids []int64)
 // RetrieveUserByEmail retrieves a user by email
 func (r *userRepositoryImpl) RetrieveUserByEmail(ctx context.Context, email string) (userEnt.User, error) {
 	var user userEnt.User
-	query := `
+	query := r.dialect.Rebind(`
 		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
 		FROM users
-		WHERE email = $1 AND deleted_at IS NULL
-	`
-	err := r.db.GetContext(ctx, &user, query, email)
+		WHERE email = ? AND deleted_at IS NULL
+	`)
+	err := r.executor(ctx).GetContext(ctx, &user, query, email)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return userEnt.User{}, fmt.Errorf("user with email %s not found", email)
+		if sqlPkg.IsNoRows(err) {
+			return userEnt.User{}, fmt.Errorf("%w: email %s", ErrUserNotFound, email)
 		}
 		return userEnt.User{}, fmt.Errorf("failed to retrieve user by email: %w", err)
 	}
@@ -242,44 +275,43 @@ func (r *userRepositoryImpl) RetrieveUserByEmails(ctx context.Context, emails []
 		return []userEnt.User{}, nil
 	}
 
-	// Convert emails to interface{} slice for query args
-	args := make([]interface{}, len(emails))
-	for i, email := range emails {
-		args[i] = email
-	}
-
-	// Create placeholders like $1, $2, $3...
-	placeholders := make([]string, len(emails))
-	for i := range emails {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	users, err := r.retrieveUsersIn(ctx, "email", emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve users by emails: %w", err)
 	}
+	return users, nil
+}
 
-	query := fmt.Sprintf(`
+// RetrieveUserByUsername retrieves a user by username
+func (r *userRepositoryImpl) RetrieveUserByUsername(ctx context.Context, username string) (userEnt.User, error) {
+	var user userEnt.User
+	query := r.dialect.Rebind(`
 		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
 		FROM users
-		WHERE email IN (%s) AND deleted_at IS NULL
-	`, strings.Join(placeholders, ", "))
-
-	var users []userEnt.User
-	err := r.db.SelectContext(ctx, &users, query, args...)
+		WHERE username = ? AND deleted_at IS NULL
+	`)
+	err := r.executor(ctx).GetContext(ctx, &user, query, username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve users by emails: %w", err)
+		if sqlPkg.IsNoRows(err) {
+			return userEnt.User{}, fmt.Errorf("%w: username %s", ErrUserNotFound, username)
+		}
+		return userEnt.User{}, fmt.Errorf("failed to retrieve user by username: %w", err)
 	}
-	return users, nil
+	return user, nil
 }
 
 // RetrieveUserByUniqueId retrieves a user by unique ID
 func (r *userRepositoryImpl) RetrieveUserByUniqueId(ctx context.Context, uniqueId string) (userEnt.User, error) {
 	var user userEnt.User
-	query := `
+	query := r.dialect.Rebind(`
 		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
 		FROM users
-		WHERE unique_id = $1 AND deleted_at IS NULL
-	`
-	err := r.db.GetContext(ctx, &user, query, uniqueId)
+		WHERE unique_id = ? AND deleted_at IS NULL
+	`)
+	err := r.executor(ctx).GetContext(ctx, &user, query, uniqueId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return userEnt.User{}, fmt.Errorf("user with unique id %s not found", uniqueId)
+		if sqlPkg.IsNoRows(err) {
+			return userEnt.User{}, fmt.Errorf("%w: unique id %s", ErrUserNotFound, uniqueId)
 		}
 		return userEnt.User{}, fmt.Errorf("failed to retrieve user by unique id: %w", err)
 	}
@@ -292,28 +324,46 @@ func (r *userRepositoryImpl) RetrieveUserByUniqueIds(ctx context.Context, unique
 		return []userEnt.User{}, nil
 	}
 
-	// Convert uniqueIds to interface{} slice for query args
-	args := make([]interface{}, len(uniqueIds))
-	for i, uniqueId := range uniqueIds {
-		args[i] = uniqueId
-	}
-
-	// Create placeholders like $1, $2, $3...
-	placeholders := make([]string, len(uniqueIds))
-	for i := range uniqueIds {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	users, err := r.retrieveUsersIn(ctx, "unique_id", uniqueIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve users by unique ids: %w", err)
 	}
+	return users, nil
+}
 
-	query := fmt.Sprintf(`
+// retrieveUsersIn retrieves users which column is one of values, sqlx expands the slice
+// to the bindvars of the dialect
+func (r *userRepositoryImpl) retrieveUsersIn(ctx context.Context, column string, values any) ([]userEnt.User, error) {
+	query, args, err := sqlx.In(fmt.Sprintf(`
 		SELECT id, role, email, unique_id, fullname, username, password, created_at, updated_at, deleted_at
 		FROM users
-		WHERE unique_id IN (%s) AND deleted_at IS NULL
-	`, strings.Join(placeholders, ", "))
+		WHERE %s IN (?) AND deleted_at IS NULL
+	`, column), values)
+	if err != nil {
+		return nil, err
+	}
 
 	var users []userEnt.User
-	err := r.db.SelectContext(ctx, &users, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve users by unique ids: %w", err)
+	if err := r.executor(ctx).SelectContext(ctx, &users, r.dialect.Rebind(query), args...); err != nil {
+		return nil, err
 	}
 	return users, nil
 }
+
+// RetrievePasswordByUniqueId retrieves password hash of a user by unique ID
+func (r *userRepositoryImpl) RetrievePasswordByUniqueId(ctx context.Context, uniqueId string) (string, error) {
+	var password string
+	query := r.dialect.Rebind(`
+		SELECT password
+		FROM users
+		WHERE unique_id = ? AND deleted_at IS NULL
+	`)
+	err := r.executor(ctx).GetContext(ctx, &password, query, uniqueId)
+	if err != nil {
+		if sqlPkg.IsNoRows(err) {
+			return "", fmt.Errorf("%w: unique id %s", ErrUserNotFound, uniqueId)
+		}
+		return "", fmt.Errorf("failed to retrieve password by unique id: %w", err)
+	}
+	return password, nil
+}
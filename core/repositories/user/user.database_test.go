@@ -0,0 +1,216 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+)
+
+// sqlDialectCase describes the driver specific part of the repository test suite
+type sqlDialectCase struct {
+	driver          string
+	uniqueViolation error
+	// bindvars of the expected query in order
+	bindvars []string
+	// returning is true when generated id is read by RETURNING clause instead of LastInsertId
+	returning bool
+	// upsert is the conflict clause of upsert query
+	upsert string
+}
+
+var sqlDialectCases = []sqlDialectCase{
+	{
+		driver:          "pgx",
+		uniqueViolation: &pgconn.PgError{Code: "23505"},
+		bindvars:        []string{"$1", "$2"},
+		returning:       true,
+		upsert:          "ON CONFLICT (unique_id) DO UPDATE SET role = EXCLUDED.role",
+	},
+	{
+		driver:          "mysql",
+		uniqueViolation: &mysql.MySQLError{Number: 1062},
+		bindvars:        []string{"?", "?"},
+		upsert:          "ON DUPLICATE KEY UPDATE role = VALUES(role)",
+	},
+}
+
+// expectInsert expects insert of a single user with the insert-id strategy of the dialect
+func expectInsert(mock sqlmock.Sqlmock, dc sqlDialectCase, id int64, err error) {
+	if dc.returning {
+		query := mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users") + "(.|\\s)*" + regexp.QuoteMeta("RETURNING id"))
+		if err != nil {
+			query.WillReturnError(err)
+			return
+		}
+		query.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+		return
+	}
+
+	exec := mock.ExpectExec("INSERT INTO users")
+	if err != nil {
+		exec.WillReturnError(err)
+		return
+	}
+	exec.WillReturnResult(sqlmock.NewResult(id, 1))
+}
+
+var userColumns = []string{"id", "role", "email", "unique_id", "fullname", "username", "password", "created_at", "updated_at", "deleted_at"}
+
+func newSQLRepository(t *testing.T, driver string) (IUserRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo, err := NewUserSQLRepository(sqlx.NewDb(db, driver))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, mock
+}
+
+// TestUserSQLRepository runs the same suite against every supported dialect
+func TestUserSQLRepository(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, dc := range sqlDialectCases {
+		t.Run(dc.driver, func(t *testing.T) {
+			t.Run("retrieve by email", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE email = " + dc.bindvars[0] + " AND deleted_at IS NULL")).
+					WithArgs("jane@example.com").
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(1, "member", "jane@example.com", "uid-1", "Jane", "jane", "hash", now, now, nil))
+
+				user, err := repo.RetrieveUserByEmail(ctx, "jane@example.com")
+				if err != nil || user.UniqueId != "uid-1" {
+					t.Errorf("Expected user uid-1, got: %+v, %v", user, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			t.Run("retrieve password", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT password")).
+					WithArgs("uid-1").
+					WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("hash"))
+
+				password, err := repo.RetrievePasswordByUniqueId(ctx, "uid-1")
+				if err != nil || password != "hash" {
+					t.Errorf("Expected password hash, got: %q, %v", password, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			t.Run("retrieve missing user", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE unique_id = " + dc.bindvars[0])).
+					WithArgs("uid-404").
+					WillReturnRows(sqlmock.NewRows(userColumns))
+
+				if _, err := repo.RetrieveUserByUniqueId(ctx, "uid-404"); !errors.Is(err, ErrUserNotFound) {
+					t.Errorf("Expected ErrUserNotFound, got: %v", err)
+				}
+			})
+
+			t.Run("retrieve by ids", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE id IN ("+dc.bindvars[0]+", "+dc.bindvars[1]+") AND deleted_at IS NULL")).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(1, "member", "a@example.com", "uid-1", "A", "a", "hash", now, now, nil).
+						AddRow(2, "member", "b@example.com", "uid-2", "B", "b", "hash", now, now, nil))
+
+				users, err := repo.RetrieveUserByIds(ctx, []int64{1, 2})
+				if err != nil || len(users) != 2 {
+					t.Errorf("Expected 2 users, got: %+v, %v", users, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			t.Run("retrieve all with pagination", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectQuery(regexp.QuoteMeta("LIMIT "+dc.bindvars[0]+" OFFSET "+dc.bindvars[1])).
+					WithArgs(10, 20).
+					WillReturnRows(sqlmock.NewRows(userColumns))
+
+				if _, err := repo.RetrieveAllUser(ctx, 20, 10); err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			t.Run("soft delete", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = "+dc.bindvars[0]+" WHERE unique_id = "+dc.bindvars[1])).
+					WithArgs(sqlmock.AnyArg(), "uid-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET deleted_at = "+dc.bindvars[0]+" WHERE unique_id = "+dc.bindvars[1])).
+					WithArgs(sqlmock.AnyArg(), "uid-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				if err := repo.DeleteUserByUniqueId(ctx, "uid-1"); err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				if err := repo.DeleteUserByUniqueId(ctx, "uid-1"); !errors.Is(err, ErrUserNotFound) {
+					t.Errorf("Expected ErrUserNotFound for deleted user, got: %v", err)
+				}
+			})
+
+			t.Run("save returns generated id", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				expectInsert(mock, dc, 7, nil)
+
+				id, err := repo.SaveUser(ctx, userEnt.User{Email: "jane@example.com", CreatedAt: now, UpdatedAt: now})
+				if err != nil || id != 7 {
+					t.Errorf("Expected id 7, got: %d, %v", id, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			t.Run("upsert by unique id", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				mock.ExpectExec(regexp.QuoteMeta(dc.upsert)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if err := repo.UpsertUser(ctx, userEnt.User{UniqueId: "uid-1", CreatedAt: now, UpdatedAt: now}); err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+			})
+
+			t.Run("save duplicate user", func(t *testing.T) {
+				repo, mock := newSQLRepository(t, dc.driver)
+				expectInsert(mock, dc, 0, dc.uniqueViolation)
+
+				_, err := repo.SaveUser(ctx, userEnt.User{Email: "jane@example.com", CreatedAt: now, UpdatedAt: now})
+				if !errors.Is(err, ErrUserAlreadyExists) {
+					t.Errorf("Expected ErrUserAlreadyExists, got: %v", err)
+				}
+			})
+		})
+	}
+}
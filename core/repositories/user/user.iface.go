@@ -4,11 +4,23 @@ import (
 	"context"
 
 	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+var (
+	// ErrUserNotFound is returned when no active (not deleted) user matches the lookup
+	ErrUserNotFound = errs.NotFound("user not found")
+	// ErrUserAlreadyExists is returned when email or username is owned by other active user,
+	// soft deleted user releases them so they can be registered again
+	ErrUserAlreadyExists = errs.Conflict("user with the same email or username already exists")
 )
 
 type IUserRepository interface {
-	SaveUser(ctx context.Context, user userEnt.User) error
+	// SaveUser inserts the user and returns its generated id
+	SaveUser(ctx context.Context, user userEnt.User) (int64, error)
 	SaveUsers(ctx context.Context, users []userEnt.User) error
+	// UpsertUser inserts the user or updates the user with the same unique ID
+	UpsertUser(ctx context.Context, user userEnt.User) error
 	UpdateUser(ctx context.Context, user userEnt.User) error
 	DeleteUserById(ctx context.Context, id int64) error
 	DeleteUserByEmail(ctx context.Context, email string) error
@@ -19,6 +31,10 @@ type IUserRepository interface {
 	RetrieveUserByIds(ctx context.Context, id []int64) ([]userEnt.User, error)
 	RetrieveUserByEmail(ctx context.Context, email string) (userEnt.User, error)
 	RetrieveUserByEmails(ctx context.Context, emails []string) ([]userEnt.User, error)
+	RetrieveUserByUsername(ctx context.Context, username string) (userEnt.User, error)
 	RetrieveUserByUniqueId(ctx context.Context, uniqueId string) (userEnt.User, error)
 	RetrieveUserByUniqueIds(ctx context.Context, uniqueId []string) ([]userEnt.User, error)
+	// RetrievePasswordByUniqueId returns password hash of the user, it is always read from
+	// the store because cached user has no password
+	RetrievePasswordByUniqueId(ctx context.Context, uniqueId string) (string, error)
 }
@@ -35,6 +35,10 @@ func NewUserMongoRepository(db *mongo.Database, collectionName string) IUserRepo
 			Keys:    bson.D{{Key: "unique_id", Value: 1}},
 			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"deleted_at": nil}),
 		},
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"deleted_at": nil}),
+		},
 		{
 			Keys: bson.D{{Key: "deleted_at", Value: 1}},
 		},
@@ -52,8 +56,9 @@ func NewUserMongoRepository(db *mongo.Database, collectionName string) IUserRepo
 	}
 }
 
-// SaveUser inserts a single user into MongoDB
-func (r *userMongoRepositoryImpl) SaveUser(ctx context.Context, user userEnt.User) error {
+// SaveUser inserts a single user into MongoDB, the returned id is derived from ObjectID
+// like ToUserEntity does
+func (r *userMongoRepositoryImpl) SaveUser(ctx context.Context, user userEnt.User) (int64, error) {
 	doc := user.ToMongoDocument()
 
 	// Ensure times are set
@@ -65,14 +70,16 @@ func (r *userMongoRepositoryImpl) SaveUser(ctx context.Context, user userEnt.Use
 		doc.UpdatedAt = now
 	}
 
-	_, err := r.collection.InsertOne(ctx, doc)
+	result, err := r.collection.InsertOne(ctx, doc)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return fmt.Errorf("user with this email or unique_id already exists: %w", err)
+			return 0, ErrUserAlreadyExists.Wrap(err)
 		}
-		return fmt.Errorf("failed to save user: %w", err)
+		return 0, fmt.Errorf("failed to save user: %w", err)
 	}
-	return nil
+
+	id, _ := result.InsertedID.(primitive.ObjectID)
+	return int64(id.Timestamp().Unix()), nil
 }
 
 // SaveUsers inserts multiple users into MongoDB
@@ -101,13 +108,49 @@ func (r *userMongoRepositoryImpl) SaveUsers(ctx context.Context, users []userEnt
 	_, err := r.collection.InsertMany(ctx, docs)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return fmt.Errorf("one or more users with duplicate email or unique_id: %w", err)
+			return ErrUserAlreadyExists.Wrap(err)
 		}
 		return fmt.Errorf("failed to save users: %w", err)
 	}
 	return nil
 }
 
+// UpsertUser inserts the user or updates the user with the same unique ID, soft deleted
+// user stays deleted
+func (r *userMongoRepositoryImpl) UpsertUser(ctx context.Context, user userEnt.User) error {
+	doc := user.ToMongoDocument()
+
+	now := time.Now()
+	if doc.CreatedAt.IsZero() {
+		doc.CreatedAt = now
+	}
+	if doc.UpdatedAt.IsZero() {
+		doc.UpdatedAt = now
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"role":       doc.Role,
+			"email":      doc.Email,
+			"fullname":   doc.Fullname,
+			"username":   doc.Username,
+			"password":   doc.Password,
+			"updated_at": doc.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"created_at": doc.CreatedAt},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"unique_id": doc.UniqueId}, update, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrUserAlreadyExists.Wrap(err)
+		}
+		return fmt.Errorf("failed to upsert user: %w", err)
+	}
+	return nil
+}
+
 // UpdateUser updates an existing user in MongoDB
 func (r *userMongoRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.User) error {
 	// Set update time
@@ -133,7 +176,7 @@ func (r *userMongoRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.U
 			"unique_id":  user.UniqueId,
 			"fullname":   user.Fullname,
 			"username":   user.Username,
-			"Password":   user.Password,
+			"password":   user.Password,
 			"updated_at": user.UpdatedAt,
 		},
 	}
@@ -141,13 +184,13 @@ func (r *userMongoRepositoryImpl) UpdateUser(ctx context.Context, user userEnt.U
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return fmt.Errorf("user with this email or unique_id already exists: %w", err)
+			return ErrUserAlreadyExists.Wrap(err)
 		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 
 	return nil
@@ -172,7 +215,7 @@ func (r *userMongoRepositoryImpl) DeleteUserById(ctx context.Context, id int64)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("user with id %d not found", id)
+		return fmt.Errorf("%w: id %d", ErrUserNotFound, id)
 	}
 
 	return nil
@@ -194,7 +237,7 @@ func (r *userMongoRepositoryImpl) DeleteUserByEmail(ctx context.Context, email s
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("user with email %s not found", email)
+		return fmt.Errorf("%w: email %s", ErrUserNotFound, email)
 	}
 
 	return nil
@@ -216,7 +259,7 @@ func (r *userMongoRepositoryImpl) DeleteUserByUniqueId(ctx context.Context, uniq
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("user with unique id %s not found", uniqueId)
+		return fmt.Errorf("%w: unique id %s", ErrUserNotFound, uniqueId)
 	}
 
 	return nil
@@ -255,7 +298,7 @@ func (r *userMongoRepositoryImpl) RetrieveUserById(ctx context.Context, id int64
 	err := r.collection.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return userEnt.User{}, fmt.Errorf("user with id %d not found", id)
+			return userEnt.User{}, fmt.Errorf("%w: id %d", ErrUserNotFound, id)
 		}
 		return userEnt.User{}, fmt.Errorf("failed to retrieve user by id: %w", err)
 	}
@@ -300,7 +343,7 @@ func (r *userMongoRepositoryImpl) RetrieveUserByEmail(ctx context.Context, email
 	err := r.collection.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return userEnt.User{}, fmt.Errorf("user with email %s not found", email)
+			return userEnt.User{}, fmt.Errorf("%w: email %s", ErrUserNotFound, email)
 		}
 		return userEnt.User{}, fmt.Errorf("failed to retrieve user by email: %w", err)
 	}
@@ -333,6 +376,22 @@ func (r *userMongoRepositoryImpl) RetrieveUserByEmails(ctx context.Context, emai
 	return userEnt.MongoDocsToUserEntities(docs), nil
 }
 
+// RetrieveUserByUsername retrieves a user by username
+func (r *userMongoRepositoryImpl) RetrieveUserByUsername(ctx context.Context, username string) (userEnt.User, error) {
+	filter := bson.M{"username": username, "deleted_at": nil}
+
+	var doc userEnt.UserMongoDocument
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return userEnt.User{}, fmt.Errorf("%w: username %s", ErrUserNotFound, username)
+		}
+		return userEnt.User{}, fmt.Errorf("failed to retrieve user by username: %w", err)
+	}
+
+	return doc.ToUserEntity(), nil
+}
+
 // RetrieveUserByUniqueId retrieves a user by unique ID
 func (r *userMongoRepositoryImpl) RetrieveUserByUniqueId(ctx context.Context, uniqueId string) (userEnt.User, error) {
 	filter := bson.M{"unique_id": uniqueId, "deleted_at": nil}
@@ -341,7 +400,7 @@ func (r *userMongoRepositoryImpl) RetrieveUserByUniqueId(ctx context.Context, un
 	err := r.collection.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return userEnt.User{}, fmt.Errorf("user with unique id %s not found", uniqueId)
+			return userEnt.User{}, fmt.Errorf("%w: unique id %s", ErrUserNotFound, uniqueId)
 		}
 		return userEnt.User{}, fmt.Errorf("failed to retrieve user by unique id: %w", err)
 	}
@@ -373,3 +432,20 @@ func (r *userMongoRepositoryImpl) RetrieveUserByUniqueIds(ctx context.Context, u
 
 	return userEnt.MongoDocsToUserEntities(docs), nil
 }
+
+// RetrievePasswordByUniqueId retrieves password hash of a user by unique ID
+func (r *userMongoRepositoryImpl) RetrievePasswordByUniqueId(ctx context.Context, uniqueId string) (string, error) {
+	filter := bson.M{"unique_id": uniqueId, "deleted_at": nil}
+	opts := options.FindOne().SetProjection(bson.M{"password": 1})
+
+	var doc userEnt.UserMongoDocument
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", fmt.Errorf("%w: unique id %s", ErrUserNotFound, uniqueId)
+		}
+		return "", fmt.Errorf("failed to retrieve password by unique id: %w", err)
+	}
+
+	return doc.Password, nil
+}
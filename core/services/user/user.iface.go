@@ -7,7 +7,15 @@ import (
 )
 
 type IUserServices interface {
-	SignUp(ctx context.Context, user userDto.SignUpDTO) error
+	SignUp(ctx context.Context, user userDto.SignUpDTO) (userDto.UserDTO, error)
+	Login(ctx context.Context, login userDto.LoginDTO) (userDto.TokenDTO, error)
+	RefreshToken(ctx context.Context, refresh userDto.RefreshTokenDTO) (userDto.TokenDTO, error)
+	Logout(ctx context.Context, refresh userDto.RefreshTokenDTO) error
+
+	GetUser(ctx context.Context, uniqueId string) (userDto.UserDTO, error)
+	ListUsers(ctx context.Context, query userDto.ListUserDTO) (userDto.UserPageDTO, error)
+	UpdateUser(ctx context.Context, uniqueId string, update userDto.UpdateUserDTO) (userDto.UserDTO, error)
+	DeleteUser(ctx context.Context, uniqueId string) error
 }
 
 type AuthService interface{}
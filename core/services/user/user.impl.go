@@ -1,21 +1,62 @@
 package user
 
 import (
+	"time"
+
 	"aidanwoods.dev/go-paseto"
+	tokenRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/token"
 	userRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/outbox"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
+	sqlPkg "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
 )
 
 var _ IUserServices = (*UserServicesImpl)(nil)
 
+// Default token lifetime used when it is not configured
+const (
+	defaultTokenExpiration        = 15 * time.Minute
+	defaultRefreshTokenExpiration = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidCredentials  = errs.Unauthorized("invalid username, email or password")
+	ErrInvalidRefreshToken = errs.Unauthorized("refresh token is invalid, expired or revoked")
+	ErrRoleNotFound        = errs.Validation("role is not registered")
+	ErrForbiddenRole       = errs.Forbidden("not authorized to grant the requested role")
+	ErrForbidden           = errs.Forbidden("not authorized to access the user")
+
+	// Repository errors are part of the service contract, caller does not need to import repository package
+	ErrUserNotFound      = userRepository.ErrUserNotFound
+	ErrUserAlreadyExists = userRepository.ErrUserAlreadyExists
+)
+
 type UserServicesImpl struct {
-	// Tokenizer will generate PASETO Token
-	tokenizer paseto.Token
+	// Token signing setup, used to generate v4 public PASETO access token
+	// and opaque refresh token
+	TokenIssuer            string
+	TokenSecretKey         paseto.V4AsymmetricSecretKey
+	TokenExpiration        time.Duration
+	RefreshTokenExpiration time.Duration
 
 	// Add service dependency below
-	UserRepo userRepository.IUserRepository
+	RBAC      *rbac.Registry
+	TxManager sqlPkg.ITxManager
+	Outbox    outbox.IStore
+	UserRepo  userRepository.IUserRepository
+	TokenRepo tokenRepository.IRefreshTokenRepository
 }
 
 func NewUserService(userSvc UserServicesImpl) IUserServices {
-	userSvc.tokenizer = paseto.NewToken()
+	if userSvc.TokenExpiration <= 0 {
+		userSvc.TokenExpiration = defaultTokenExpiration
+	}
+	if userSvc.RefreshTokenExpiration <= 0 {
+		userSvc.RefreshTokenExpiration = defaultRefreshTokenExpiration
+	}
+	if userSvc.TxManager == nil {
+		userSvc.TxManager = sqlPkg.NopTxManager{}
+	}
 	return &userSvc
 }
@@ -0,0 +1,132 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+
+	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// authorizeUserAccess allows the user to access their own data, accessing other user
+// requires the given permission.
+func (u *UserServicesImpl) authorizeUserAccess(ctx context.Context, uniqueId, permission string) error {
+	if subject, ok := auth.SubjectFromContext(ctx); ok && subject.UniqueId == uniqueId {
+		return nil
+	}
+
+	if err := u.RBAC.Authorize(ctx, permission); err != nil {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func (u *UserServicesImpl) GetUser(ctx context.Context, uniqueId string) (userDto.UserDTO, error) {
+	if err := u.authorizeUserAccess(ctx, uniqueId, userEnt.PermissionUserRead); err != nil {
+		return userDto.UserDTO{}, err
+	}
+
+	user, err := u.UserRepo.RetrieveUserByUniqueId(ctx, uniqueId)
+	if err != nil {
+		return userDto.UserDTO{}, err
+	}
+
+	return userDto.FromUserEntity(user), nil
+}
+
+func (u *UserServicesImpl) ListUsers(ctx context.Context, query userDto.ListUserDTO) (userDto.UserPageDTO, error) {
+	if err := u.RBAC.Authorize(ctx, userEnt.PermissionUserRead); err != nil {
+		return userDto.UserPageDTO{}, ErrForbidden
+	}
+
+	query = query.Normalize()
+
+	// Fetch one more row to know whether next page exists without counting the table
+	users, err := u.UserRepo.RetrieveAllUser(ctx, query.Offset(), query.PageSize+1)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error retrieve users", "page", query.Page, "error", err)
+		return userDto.UserPageDTO{}, err
+	}
+
+	hasNext := len(users) > query.PageSize
+	if hasNext {
+		users = users[:query.PageSize]
+	}
+
+	page := userDto.UserPageDTO{
+		Users:    make([]userDto.UserDTO, 0, len(users)),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		HasNext:  hasNext,
+	}
+	for _, user := range users {
+		page.Users = append(page.Users, userDto.FromUserEntity(user))
+	}
+
+	return page, nil
+}
+
+func (u *UserServicesImpl) UpdateUser(ctx context.Context, uniqueId string, update userDto.UpdateUserDTO) (userDto.UserDTO, error) {
+	if err := u.authorizeUserAccess(ctx, uniqueId, userEnt.PermissionUserWrite); err != nil {
+		return userDto.UserDTO{}, err
+	}
+
+	// Changing role is always a privileged operation, even for own account
+	if update.Role != nil {
+		if !u.RBAC.HasRole(*update.Role) {
+			return userDto.UserDTO{}, ErrRoleNotFound
+		}
+		if err := u.RBAC.Authorize(ctx, userEnt.PermissionUserGrantRole); err != nil {
+			return userDto.UserDTO{}, ErrForbiddenRole
+		}
+	}
+
+	var user userEnt.User
+	err := u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := u.UserRepo.RetrieveUserByUniqueId(ctx, uniqueId)
+		if err != nil {
+			return err
+		}
+
+		user, err = update.ApplyTo(current)
+		if err != nil {
+			return err
+		}
+
+		if err := u.UserRepo.UpdateUser(ctx, user); err != nil {
+			if errs.KindOf(err) != errs.KindInternal {
+				return err
+			}
+			slog.ErrorContext(ctx, "Error update user", "unique_id", uniqueId, "error", err)
+			return err
+		}
+
+		return u.emitEvent(ctx, userEnt.EventUserUpdated, user.ToEvent())
+	})
+	if err != nil {
+		return userDto.UserDTO{}, err
+	}
+
+	return userDto.FromUserEntity(user), nil
+}
+
+func (u *UserServicesImpl) DeleteUser(ctx context.Context, uniqueId string) error {
+	if err := u.authorizeUserAccess(ctx, uniqueId, userEnt.PermissionUserDelete); err != nil {
+		return err
+	}
+
+	return u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.UserRepo.DeleteUserByUniqueId(ctx, uniqueId); err != nil {
+			if errs.KindOf(err) != errs.KindInternal {
+				return err
+			}
+			slog.ErrorContext(ctx, "Error delete user", "unique_id", uniqueId, "error", err)
+			return err
+		}
+
+		return u.emitEvent(ctx, userEnt.EventUserDeleted, userEnt.UserEvent{UniqueId: uniqueId})
+	})
+}
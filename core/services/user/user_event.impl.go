@@ -0,0 +1,23 @@
+package user
+
+import (
+	"context"
+
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/outbox"
+)
+
+// emitEvent writes user event to the outbox, it must be called with ctx of the
+// transaction that changes the user. Event is dropped when outbox is not configured.
+func (u *UserServicesImpl) emitEvent(ctx context.Context, eventType string, payload userEnt.UserEvent) error {
+	if u.Outbox == nil {
+		return nil
+	}
+
+	event, err := outbox.NewEvent(eventType, payload.UniqueId, payload)
+	if err != nil {
+		return err
+	}
+
+	return u.Outbox.Add(ctx, event)
+}
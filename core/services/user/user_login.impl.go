@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/rs/xid"
+	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	userRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (u *UserServicesImpl) signToken(user userEnt.User, now time.Time) (string, time.Time) {
+	expireAt := now.Add(u.TokenExpiration)
+
+	token := paseto.NewToken()
+	token.SetJti(xid.New().String())
+	token.SetIssuer(u.TokenIssuer)
+	token.SetSubject(user.UniqueId)
+	token.SetIssuedAt(now)
+	token.SetNotBefore(now)
+	token.SetExpiration(expireAt)
+	token.SetString(auth.ClaimRole, user.Role)
+
+	return token.V4Sign(u.TokenSecretKey, nil), expireAt
+}
+
+// issueTokens signs access token and persist a new refresh token in the family
+func (u *UserServicesImpl) issueTokens(ctx context.Context, user userEnt.User, familyId string) (userDto.TokenDTO, error) {
+	now := time.Now().UTC()
+	accessToken, expireAt := u.signToken(user, now)
+
+	refreshToken, refreshExpireAt, err := u.saveRefreshToken(ctx, user, familyId, now)
+	if err != nil {
+		return userDto.TokenDTO{}, err
+	}
+
+	userData := userDto.FromUserEntity(user)
+	return userDto.TokenDTO{
+		User:            &userData,
+		Token:           accessToken,
+		ExpireAt:        &expireAt,
+		RefreshToken:    refreshToken,
+		RefreshExpireAt: &refreshExpireAt,
+	}, nil
+}
+
+// unknownUserPasswordHash is compared on login of unknown user, so response time does not
+// reveal whether the account exists. Its cost is the cost of sign-up hash.
+var unknownUserPasswordHash = []byte("$2a$10$hK6WqKjTZR8B830ICx5NbOZlGg2gWwUItODYbdPukTPURGdTsWq9u")
+
+func (u *UserServicesImpl) retrieveLoginUser(ctx context.Context, login userDto.LoginDTO) (userEnt.User, error) {
+	if login.Email != "" {
+		return u.UserRepo.RetrieveUserByEmail(ctx, login.Email)
+	}
+	return u.UserRepo.RetrieveUserByUsername(ctx, login.Username)
+}
+
+func (u *UserServicesImpl) Login(ctx context.Context, login userDto.LoginDTO) (userDto.TokenDTO, error) {
+	if (login.Email == "" && login.Username == "") || login.Password == "" {
+		return userDto.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	user, err := u.retrieveLoginUser(ctx, login)
+	if err != nil {
+		fields := []any{"email", login.Email, "username", login.Username, "error", err}
+		if !errors.Is(err, userRepository.ErrUserNotFound) {
+			slog.ErrorContext(ctx, "Error retrieve user for login", fields...)
+			return userDto.TokenDTO{}, err
+		}
+
+		bcrypt.CompareHashAndPassword(unknownUserPasswordHash, []byte(login.Password))
+		slog.WarnContext(ctx, "Login attempt of unknown user", fields...)
+		return userDto.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	// Password hash is not cached, it is always read from the store
+	password, err := u.UserRepo.RetrievePasswordByUniqueId(ctx, user.UniqueId)
+	if err != nil {
+		fields := []any{"unique_id", user.UniqueId, "error", err}
+		if !errors.Is(err, userRepository.ErrUserNotFound) {
+			slog.ErrorContext(ctx, "Error retrieve password for login", fields...)
+			return userDto.TokenDTO{}, err
+		}
+
+		// User is deleted after the lookup
+		slog.WarnContext(ctx, "Login attempt of deleted user", fields...)
+		return userDto.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(password), []byte(login.Password)); err != nil {
+		fields := []any{"unique_id", user.UniqueId}
+		slog.WarnContext(ctx, "Login attempt with mismatch password", fields...)
+		return userDto.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	// Every login starts a new refresh token family
+	token, err := u.issueTokens(ctx, user, xid.New().String())
+	if err != nil {
+		fields := []any{"unique_id", user.UniqueId, "error", err}
+		slog.ErrorContext(ctx, "Error issue login token", fields...)
+		return userDto.TokenDTO{}, err
+	}
+
+	return token, nil
+}
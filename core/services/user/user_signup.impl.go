@@ -2,26 +2,85 @@ package user
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	userRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/user"
 )
 
-// func (u *UserServicesImpl) signToken() {}
+// resolveSignUpRole returns the default role when none is requested, other roles
+// can only be granted by authenticated caller that own grant-role permission.
+func (u *UserServicesImpl) resolveSignUpRole(ctx context.Context, role string) (string, error) {
+	if role == "" || role == u.RBAC.DefaultRole() {
+		return u.RBAC.DefaultRole(), nil
+	}
+
+	if !u.RBAC.HasRole(role) {
+		return "", ErrRoleNotFound
+	}
+
+	if err := u.RBAC.Authorize(ctx, userEnt.PermissionUserGrantRole); err != nil {
+		return "", ErrForbiddenRole
+	}
+
+	return role, nil
+}
+
+// ensureUserAvailable returns ErrUserAlreadyExists when the email or username is owned by other active user
+func (u *UserServicesImpl) ensureUserAvailable(ctx context.Context, email, username string) error {
+	if _, err := u.UserRepo.RetrieveUserByEmail(ctx, email); err == nil {
+		return ErrUserAlreadyExists
+	} else if !errors.Is(err, userRepository.ErrUserNotFound) {
+		return err
+	}
+
+	if _, err := u.UserRepo.RetrieveUserByUsername(ctx, username); err == nil {
+		return ErrUserAlreadyExists
+	} else if !errors.Is(err, userRepository.ErrUserNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (u *UserServicesImpl) SignUp(ctx context.Context, registerUser userDto.SignUpDTO) (userDto.UserDTO, error) {
+	role, err := u.resolveSignUpRole(ctx, registerUser.Role)
+	if err != nil {
+		fields := []any{"email", registerUser.Email, "role", registerUser.Role, "error", err}
+		slog.WarnContext(ctx, "Sign-up with unauthorized role", fields...)
+		return userDto.UserDTO{}, err
+	}
+	registerUser.Role = role
 
-func (u *UserServicesImpl) SignUp(ctx context.Context, registerUser userDto.SignUpDTO) error {
 	user, err := registerUser.ToUserEntity()
 	if err != nil {
 		fields := []any{"name", registerUser.Fullname, "email", registerUser.Email} // because of error, let's get user data from parameter
 		slog.Error("Error convert sign-up user to entity", fields...)
-		return err
+		return userDto.UserDTO{}, err
 	}
 
-	if err := u.UserRepo.SaveUser(ctx, user); err != nil {
-		fields := []any{"name", registerUser.Fullname, "email", registerUser.Email} // more secure if getting data from parameter
-		slog.Error("Error convert sign-up user to entity", fields...)
-		return err
+	// Availability check and insert are one unit of work, row written by other repository
+	// with the same ctx is committed together with the user
+	err = u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.ensureUserAvailable(ctx, registerUser.Email, registerUser.Username); err != nil {
+			return err
+		}
+
+		id, err := u.UserRepo.SaveUser(ctx, user)
+		if err != nil {
+			fields := []any{"name", registerUser.Fullname, "email", registerUser.Email} // more secure if getting data from parameter
+			slog.Error("Error convert sign-up user to entity", fields...)
+			return err
+		}
+		user.Id = id
+
+		return u.emitEvent(ctx, userEnt.EventUserSignedUp, user.ToEvent())
+	})
+	if err != nil {
+		return userDto.UserDTO{}, err
 	}
 
-	return nil
+	return userDto.FromUserEntity(user), nil
 }
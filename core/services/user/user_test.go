@@ -1 +1,444 @@
 package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	tokenRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/token"
+	userRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/user"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/outbox"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserRepository is an in-memory IUserRepository used by service tests
+type fakeUserRepository struct {
+	userRepository.IUserRepository
+	users []userEnt.User
+}
+
+func (f *fakeUserRepository) SaveUser(ctx context.Context, user userEnt.User) (int64, error) {
+	user.Id = int64(len(f.users) + 1)
+	f.users = append(f.users, user)
+	return user.Id, nil
+}
+
+func (f *fakeUserRepository) RetrieveUserByEmail(ctx context.Context, email string) (userEnt.User, error) {
+	for _, user := range f.users {
+		if user.Email == email && user.DeletedAt == nil {
+			return user, nil
+		}
+	}
+	return userEnt.User{}, fmt.Errorf("%w: email %s", userRepository.ErrUserNotFound, email)
+}
+
+func (f *fakeUserRepository) RetrieveUserByUsername(ctx context.Context, username string) (userEnt.User, error) {
+	for _, user := range f.users {
+		if user.Username == username && user.DeletedAt == nil {
+			return user, nil
+		}
+	}
+	return userEnt.User{}, fmt.Errorf("%w: username %s", userRepository.ErrUserNotFound, username)
+}
+
+func (f *fakeUserRepository) RetrieveUserByUniqueId(ctx context.Context, uniqueId string) (userEnt.User, error) {
+	for _, user := range f.users {
+		if user.UniqueId == uniqueId && user.DeletedAt == nil {
+			return user, nil
+		}
+	}
+	return userEnt.User{}, fmt.Errorf("%w: unique id %s", userRepository.ErrUserNotFound, uniqueId)
+}
+
+func (f *fakeUserRepository) RetrievePasswordByUniqueId(ctx context.Context, uniqueId string) (string, error) {
+	user, err := f.RetrieveUserByUniqueId(ctx, uniqueId)
+	return user.Password, err
+}
+
+func (f *fakeUserRepository) RetrieveAllUser(ctx context.Context, offset, limit int) ([]userEnt.User, error) {
+	var users []userEnt.User
+	for _, user := range f.users {
+		if user.DeletedAt == nil {
+			users = append(users, user)
+		}
+	}
+	if offset >= len(users) {
+		return nil, nil
+	}
+	return users[offset:min(offset+limit, len(users))], nil
+}
+
+func (f *fakeUserRepository) UpdateUser(ctx context.Context, user userEnt.User) error {
+	for i := range f.users {
+		if f.users[i].UniqueId == user.UniqueId && f.users[i].DeletedAt == nil {
+			f.users[i] = user
+			return nil
+		}
+	}
+	return userRepository.ErrUserNotFound
+}
+
+func (f *fakeUserRepository) DeleteUserByUniqueId(ctx context.Context, uniqueId string) error {
+	for i := range f.users {
+		if f.users[i].UniqueId == uniqueId && f.users[i].DeletedAt == nil {
+			now := time.Now()
+			f.users[i].DeletedAt = &now
+			return nil
+		}
+	}
+	return userRepository.ErrUserNotFound
+}
+
+func newTestRBAC(t *testing.T) *rbac.Registry {
+	t.Helper()
+
+	registry, err := rbac.NewRegistry(&rbac.RBACConfig{
+		RBACDefaultRole: userEnt.RoleMember,
+		RBACPolicy:      userEnt.DefaultRBACPolicy,
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry returned unexpected error: %v", err)
+	}
+	return registry
+}
+
+func newTestUserService(t *testing.T) (IUserServices, paseto.V4AsymmetricSecretKey) {
+	t.Helper()
+
+	secretKey := paseto.NewV4AsymmetricSecretKey()
+	svc := NewUserService(UserServicesImpl{
+		TokenIssuer:     "test-issuer",
+		TokenSecretKey:  secretKey,
+		TokenExpiration: time.Minute,
+		RBAC:            newTestRBAC(t),
+		UserRepo:        &fakeUserRepository{},
+		TokenRepo:       tokenRepository.NewRefreshTokenMemoryRepository(),
+	})
+
+	_, err := svc.SignUp(context.Background(), userDto.SignUpDTO{
+		Email:    "jane@example.com",
+		Fullname: "Jane Doe",
+		Username: "jane",
+		Password: "Supersecret!",
+	})
+	if err != nil {
+		t.Fatalf("SignUp returned unexpected error: %v", err)
+	}
+
+	return svc, secretKey
+}
+
+func TestLoginIssueToken(t *testing.T) {
+	svc, secretKey := newTestUserService(t)
+
+	cases := map[string]userDto.LoginDTO{
+		"by email":    {Email: "jane@example.com", Password: "Supersecret!"},
+		"by username": {Username: "jane", Password: "Supersecret!"},
+	}
+
+	for name, login := range cases {
+		t.Run(name, func(t *testing.T) {
+			token, err := svc.Login(context.Background(), login)
+			if err != nil {
+				t.Fatalf("Login returned unexpected error: %v", err)
+			}
+
+			if token.User == nil || token.User.Username != "jane" {
+				t.Fatalf("Expected token user jane, got: %+v", token.User)
+			}
+
+			parser := paseto.NewParser()
+			parser.AddRule(paseto.IssuedBy("test-issuer"))
+			parsed, err := parser.ParseV4Public(secretKey.Public(), token.Token, nil)
+			if err != nil {
+				t.Fatalf("Issued token cannot be verified: %v", err)
+			}
+
+			subject, _ := parsed.GetSubject()
+			if subject != token.User.UniqueId {
+				t.Errorf("Expected subject %s, got: %s", token.User.UniqueId, subject)
+			}
+
+			role, _ := parsed.GetString("role")
+			if role != "member" {
+				t.Errorf("Expected role member, got: %s", role)
+			}
+		})
+	}
+}
+
+func TestLoginInvalidCredentials(t *testing.T) {
+	svc, _ := newTestUserService(t)
+
+	cases := map[string]userDto.LoginDTO{
+		"wrong password":   {Email: "jane@example.com", Password: "wrong"},
+		"unknown user":     {Username: "john", Password: "Supersecret!"},
+		"missing password": {Username: "jane"},
+		"missing identity": {Password: "Supersecret!"},
+	}
+
+	for name, login := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Login(context.Background(), login)
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("Expected ErrInvalidCredentials, got: %v", err)
+			}
+		})
+	}
+}
+
+// failingUserRepository fails every lookup, e.g. database is unreachable
+type failingUserRepository struct {
+	fakeUserRepository
+	err error
+}
+
+func (f *failingUserRepository) RetrieveUserByEmail(ctx context.Context, email string) (userEnt.User, error) {
+	return userEnt.User{}, f.err
+}
+
+func TestLoginRepositoryError(t *testing.T) {
+	errUnreachable := errors.New("connection refused")
+	svc := NewUserService(UserServicesImpl{
+		RBAC:     newTestRBAC(t),
+		UserRepo: &failingUserRepository{err: errUnreachable},
+	})
+
+	_, err := svc.Login(context.Background(), userDto.LoginDTO{Email: "jane@example.com", Password: "Supersecret!"})
+	if !errors.Is(err, errUnreachable) || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected repository error, got: %v", err)
+	}
+}
+
+func TestUnknownUserPasswordHash(t *testing.T) {
+	cost, err := bcrypt.Cost(unknownUserPasswordHash)
+	if err != nil || cost != bcrypt.DefaultCost {
+		t.Errorf("Expected valid hash of default cost, got: %d, %v", cost, err)
+	}
+}
+
+func TestSignUpRoleAssignment(t *testing.T) {
+	adminCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: "admin-1", Role: userEnt.RoleAdmin})
+	memberCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: "member-1", Role: userEnt.RoleMember})
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		role     string
+		wantRole string
+		wantErr  error
+	}{
+		{"empty role get default", context.Background(), "", userEnt.RoleMember, nil},
+		{"default role is allowed", context.Background(), userEnt.RoleMember, userEnt.RoleMember, nil},
+		{"anonymous cannot grant admin", context.Background(), userEnt.RoleAdmin, "", ErrForbiddenRole},
+		{"member cannot grant admin", memberCtx, userEnt.RoleAdmin, "", ErrForbiddenRole},
+		{"admin can grant admin", adminCtx, userEnt.RoleAdmin, userEnt.RoleAdmin, nil},
+		{"unknown role is rejected", adminCtx, "superuser", "", ErrRoleNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeUserRepository{}
+			svc := NewUserService(UserServicesImpl{RBAC: newTestRBAC(t), UserRepo: repo})
+
+			user, err := svc.SignUp(tc.ctx, userDto.SignUpDTO{Role: tc.role, Email: "jane@example.com", Username: "jane", Password: "Supersecret!"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+
+			if tc.wantErr == nil && (repo.users[0].Role != tc.wantRole || user.UniqueId != repo.users[0].UniqueId) {
+				t.Errorf("Expected role %s and created unique id, got: %+v", tc.wantRole, user)
+			}
+		})
+	}
+}
+
+func TestSignUpDuplicateUser(t *testing.T) {
+	svc, _ := newTestUserService(t)
+
+	cases := map[string]userDto.SignUpDTO{
+		"same email":    {Email: "jane@example.com", Username: "jane2", Password: "Supersecret!"},
+		"same username": {Email: "jane2@example.com", Username: "jane", Password: "Supersecret!"},
+	}
+
+	for name, signUp := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.SignUp(context.Background(), signUp); !errors.Is(err, ErrUserAlreadyExists) {
+				t.Errorf("Expected ErrUserAlreadyExists, got: %v", err)
+			}
+		})
+	}
+}
+
+// fakeOutbox records events added by the service
+type fakeOutbox struct {
+	outbox.IStore
+	events []outbox.Event
+}
+
+func (f *fakeOutbox) Add(ctx context.Context, events ...outbox.Event) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+func TestUserDomainEvents(t *testing.T) {
+	repo := &fakeUserRepository{}
+	events := &fakeOutbox{}
+	svc := NewUserService(UserServicesImpl{RBAC: newTestRBAC(t), UserRepo: repo, Outbox: events})
+
+	user, err := svc.SignUp(context.Background(), userDto.SignUpDTO{Email: "jane@example.com", Username: "jane", Password: "Supersecret!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ownerCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: user.UniqueId, Role: userEnt.RoleMember})
+	fullname := "Jane Doe"
+	if _, err := svc.UpdateUser(ownerCtx, user.UniqueId, userDto.UpdateUserDTO{Fullname: &fullname}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteUser(ownerCtx, user.UniqueId); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{userEnt.EventUserSignedUp, userEnt.EventUserUpdated, userEnt.EventUserDeleted}
+	if len(events.events) != len(expected) {
+		t.Fatalf("Expected %d events, got: %+v", len(expected), events.events)
+	}
+	for i, event := range events.events {
+		if event.Type != expected[i] || event.AggregateId != user.UniqueId || event.Id == "" {
+			t.Errorf("Expected %s event of %s, got: %+v", expected[i], user.UniqueId, event)
+		}
+		if strings.Contains(string(event.Payload), "password") {
+			t.Errorf("Expected payload without password, got: %s", event.Payload)
+		}
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	svc, _ := newTestUserService(t)
+	ctx := context.Background()
+
+	login, err := svc.Login(ctx, userDto.LoginDTO{Username: "jane", Password: "Supersecret!"})
+	if err != nil {
+		t.Fatalf("Login returned unexpected error: %v", err)
+	}
+	if login.RefreshToken == "" || login.RefreshExpireAt == nil {
+		t.Fatalf("Expected refresh token on login, got: %+v", login)
+	}
+
+	rotated, err := svc.RefreshToken(ctx, userDto.RefreshTokenDTO{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken returned unexpected error: %v", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken || rotated.Token == "" {
+		t.Fatalf("Expected rotated tokens, got: %+v", rotated)
+	}
+
+	// Reusing the first refresh token revokes the whole family, including the rotated one
+	if _, err := svc.RefreshToken(ctx, userDto.RefreshTokenDTO{RefreshToken: login.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("Expected ErrInvalidRefreshToken on reuse, got: %v", err)
+	}
+	if _, err := svc.RefreshToken(ctx, userDto.RefreshTokenDTO{RefreshToken: rotated.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected rotated token to be revoked after reuse, got: %v", err)
+	}
+}
+
+func TestLogoutRevokeRefreshToken(t *testing.T) {
+	svc, _ := newTestUserService(t)
+	ctx := context.Background()
+
+	login, err := svc.Login(ctx, userDto.LoginDTO{Username: "jane", Password: "Supersecret!"})
+	if err != nil {
+		t.Fatalf("Login returned unexpected error: %v", err)
+	}
+
+	if err := svc.Logout(ctx, userDto.RefreshTokenDTO{RefreshToken: login.RefreshToken}); err != nil {
+		t.Fatalf("Logout returned unexpected error: %v", err)
+	}
+
+	// Logout is idempotent
+	if err := svc.Logout(ctx, userDto.RefreshTokenDTO{RefreshToken: login.RefreshToken}); err != nil {
+		t.Errorf("Expected second logout to succeed, got: %v", err)
+	}
+
+	if _, err := svc.RefreshToken(ctx, userDto.RefreshTokenDTO{RefreshToken: login.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken after logout, got: %v", err)
+	}
+
+	if err := svc.Logout(ctx, userDto.RefreshTokenDTO{RefreshToken: "malformed"}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken for malformed token, got: %v", err)
+	}
+}
+
+func TestUserCRUDAuthorization(t *testing.T) {
+	repo := &fakeUserRepository{}
+	svc := NewUserService(UserServicesImpl{RBAC: newTestRBAC(t), UserRepo: repo})
+	for _, username := range []string{"jane", "john", "jack"} {
+		_, err := svc.SignUp(context.Background(), userDto.SignUpDTO{Email: username + "@example.com", Username: username, Password: "Supersecret!"})
+		if err != nil {
+			t.Fatalf("SignUp returned unexpected error: %v", err)
+		}
+	}
+
+	jane, john := repo.users[0], repo.users[1]
+	janeCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: jane.UniqueId, Role: userEnt.RoleMember})
+	adminCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: "admin-1", Role: userEnt.RoleAdmin})
+
+	if user, err := svc.GetUser(janeCtx, jane.UniqueId); err != nil || user.Username != "jane" {
+		t.Errorf("Expected own user to be readable, got: %+v, %v", user, err)
+	}
+	if _, err := svc.GetUser(janeCtx, john.UniqueId); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden reading other user, got: %v", err)
+	}
+	if _, err := svc.GetUser(adminCtx, "unknown"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got: %v", err)
+	}
+
+	newName := "Jane Updated"
+	if user, err := svc.UpdateUser(janeCtx, jane.UniqueId, userDto.UpdateUserDTO{Fullname: &newName}); err != nil || user.Fullname != newName {
+		t.Errorf("Expected own user to be updatable, got: %+v, %v", user, err)
+	}
+	adminRole := userEnt.RoleAdmin
+	if _, err := svc.UpdateUser(janeCtx, jane.UniqueId, userDto.UpdateUserDTO{Role: &adminRole}); !errors.Is(err, ErrForbiddenRole) {
+		t.Errorf("Expected ErrForbiddenRole on self promotion, got: %v", err)
+	}
+	if user, err := svc.UpdateUser(adminCtx, john.UniqueId, userDto.UpdateUserDTO{Role: &adminRole}); err != nil || user.Role != userEnt.RoleAdmin {
+		t.Errorf("Expected admin to grant role, got: %+v, %v", user, err)
+	}
+
+	if _, err := svc.ListUsers(janeCtx, userDto.ListUserDTO{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden listing users as member, got: %v", err)
+	}
+	page, err := svc.ListUsers(adminCtx, userDto.ListUserDTO{Page: 1, PageSize: 2})
+	if err != nil || len(page.Users) != 2 || !page.HasNext {
+		t.Errorf("Expected first page with next page, got: %+v, %v", page, err)
+	}
+
+	if err := svc.DeleteUser(janeCtx, john.UniqueId); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden deleting other user, got: %v", err)
+	}
+	if err := svc.DeleteUser(janeCtx, jane.UniqueId); err != nil {
+		t.Errorf("Expected own user to be deletable, got: %v", err)
+	}
+	if err := svc.DeleteUser(adminCtx, jane.UniqueId); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound on deleted user, got: %v", err)
+	}
+
+	page, err = svc.ListUsers(adminCtx, userDto.ListUserDTO{Page: 1, PageSize: 2})
+	if err != nil || len(page.Users) != 2 || page.HasNext {
+		t.Errorf("Expected deleted user to be excluded, got: %+v, %v", page, err)
+	}
+
+	// Deleted user releases its email and username
+	if _, err := svc.SignUp(context.Background(), userDto.SignUpDTO{Email: "jane@example.com", Username: "jane", Password: "Supersecret!"}); err != nil {
+		t.Errorf("Expected email and username of deleted user to be available, got: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	userDto "github.com/wahyurudiyan/go-boilerplate/core/dto/user"
+	tokenEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/token"
+	userEnt "github.com/wahyurudiyan/go-boilerplate/core/entities/user"
+	tokenRepository "github.com/wahyurudiyan/go-boilerplate/core/repositories/token"
+)
+
+// Opaque refresh token format is "<family_id>.<secret>", only sha256 of the secret is stored
+const refreshTokenSeparator = "."
+
+func hashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseRefreshToken(refreshToken string) (familyId, id string, ok bool) {
+	familyId, secret, found := strings.Cut(refreshToken, refreshTokenSeparator)
+	if !found || familyId == "" || secret == "" {
+		return "", "", false
+	}
+	return familyId, hashRefreshSecret(secret), true
+}
+
+func (u *UserServicesImpl) saveRefreshToken(ctx context.Context, user userEnt.User, familyId string, now time.Time) (string, time.Time, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", time.Time{}, err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	expireAt := now.Add(u.RefreshTokenExpiration)
+	err := u.TokenRepo.SaveRefreshToken(ctx, tokenEnt.RefreshToken{
+		Id:        hashRefreshSecret(secret),
+		FamilyId:  familyId,
+		UniqueId:  user.UniqueId,
+		CreatedAt: now,
+		ExpireAt:  expireAt,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return familyId + refreshTokenSeparator + secret, expireAt, nil
+}
+
+// RefreshToken rotates refresh token, using a consumed token revokes its whole family
+// because it means the token has been stolen and used by someone else.
+func (u *UserServicesImpl) RefreshToken(ctx context.Context, refresh userDto.RefreshTokenDTO) (userDto.TokenDTO, error) {
+	familyId, id, ok := parseRefreshToken(refresh.RefreshToken)
+	if !ok {
+		return userDto.TokenDTO{}, ErrInvalidRefreshToken
+	}
+
+	stored, err := u.TokenRepo.ConsumeRefreshToken(ctx, familyId, id)
+	if err != nil {
+		if errors.Is(err, tokenRepository.ErrRefreshTokenReused) {
+			fields := []any{"family_id", familyId, "unique_id", stored.UniqueId}
+			slog.WarnContext(ctx, "Refresh token reuse detected, revoking token family", fields...)
+			if err := u.TokenRepo.RevokeFamily(ctx, familyId); err != nil {
+				return userDto.TokenDTO{}, err
+			}
+			return userDto.TokenDTO{}, ErrInvalidRefreshToken
+		}
+		if errors.Is(err, tokenRepository.ErrRefreshTokenNotFound) {
+			return userDto.TokenDTO{}, ErrInvalidRefreshToken
+		}
+		return userDto.TokenDTO{}, err
+	}
+
+	// Reload user, so role changes are applied and deleted user cannot refresh anymore
+	user, err := u.UserRepo.RetrieveUserByUniqueId(ctx, stored.UniqueId)
+	if err != nil {
+		fields := []any{"unique_id", stored.UniqueId, "error", err}
+		slog.WarnContext(ctx, "Error retrieve user for refresh token", fields...)
+		if err := u.TokenRepo.RevokeFamily(ctx, familyId); err != nil {
+			return userDto.TokenDTO{}, err
+		}
+		return userDto.TokenDTO{}, ErrInvalidRefreshToken
+	}
+
+	return u.issueTokens(ctx, user, familyId)
+}
+
+// Logout revokes the refresh token family. Well-formed token which is unknown or already
+// revoked is ignored so logout is idempotent, malformed token returns ErrInvalidRefreshToken.
+func (u *UserServicesImpl) Logout(ctx context.Context, refresh userDto.RefreshTokenDTO) error {
+	familyId, id, ok := parseRefreshToken(refresh.RefreshToken)
+	if !ok {
+		return ErrInvalidRefreshToken
+	}
+
+	// Make sure the caller owns a token of the family before revoking it
+	_, err := u.TokenRepo.ConsumeRefreshToken(ctx, familyId, id)
+	if err != nil {
+		if errors.Is(err, tokenRepository.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		if !errors.Is(err, tokenRepository.ErrRefreshTokenReused) {
+			return err
+		}
+	}
+
+	return u.TokenRepo.RevokeFamily(ctx, familyId)
+}
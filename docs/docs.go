@@ -25,7 +25,7 @@ const docTemplate = `{
     "paths": {
         "/health-check": {
             "get": {
-                "description": "the health check endpoint provide the status of server.",
+                "description": "endpoint that run every dependency check, it return 503 when critical check fails or the service is shutting down.",
                 "consumes": [
                     "*/*"
                 ],
@@ -35,22 +35,470 @@ const docTemplate = `{
                 "tags": [
                     "Health Check Endpoint"
                 ],
-                "summary": "Show the status of server.",
+                "summary": "Readiness probe.",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Ready",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/common.RESTBody-health_Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-health_Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "endpoint that return active users ordered by creation, requires users:read permission.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "List users endpoint.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, start from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, maximum 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-user_UserPageDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "endpoint that authenticate user by email or username and password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "Login user endpoint.",
+                "parameters": [
+                    {
+                        "description": "Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.LoginDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-user_TokenDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "description": "endpoint that revoke refresh token and every token rotated from the same login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "Logout user endpoint.",
+                "parameters": [
+                    {
+                        "description": "Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.RefreshTokenDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "description": "endpoint that return unique_id and role of the bearer token owner.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "Authenticated user endpoint.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-auth_Subject"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/signup": {
+            "post": {
+                "description": "endpoint that handle user register.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "SignUp user endpoint.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token, required to grant non-default role",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retry with the same key replays the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.SignUpDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-user_UserDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with different request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/token/refresh": {
+            "post": {
+                "description": "endpoint that exchange refresh token with new access and refresh token, the old refresh token cannot be used anymore.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "Refresh token endpoint.",
+                "parameters": [
+                    {
+                        "description": "Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.RefreshTokenDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-user_TokenDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{unique_id}": {
+            "get": {
+                "description": "endpoint that return user by unique_id, other than own account requires users:read permission.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "Get user endpoint.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User unique id",
+                        "name": "unique_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-user_UserDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "endpoint that soft delete a user, other than own account requires users:delete permission.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Endpoint"
+                ],
+                "summary": "Delete user endpoint.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User unique id",
+                        "name": "unique_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retry with the same key replays the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with different request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
                         }
                     }
                 }
-            }
-        },
-        "/users/signup": {
-            "post": {
-                "description": "endpoint that handle user register.",
+            },
+            "patch": {
+                "description": "endpoint that update given fields of a user, other than own account requires users:write permission and changing role requires users:grant-role permission.",
                 "consumes": [
-                    "*/*"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -58,22 +506,35 @@ const docTemplate = `{
                 "tags": [
                     "User Endpoint"
                 ],
-                "summary": "SignUp user endpoint.",
+                "summary": "Update user endpoint.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Request body type",
+                        "description": "Bearer token",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "User unique id",
+                        "name": "unique_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retry with the same key replays the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Request Body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.SignUpDTO"
+                            "$ref": "#/definitions/user.UpdateUserDTO"
                         }
                     }
                 ],
@@ -81,7 +542,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/common.RESTBody-any"
+                            "$ref": "#/definitions/common.RESTBody-user_UserDTO"
                         }
                     },
                     "400": {
@@ -89,12 +550,53 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/common.RESTBody-any"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key reused with different request",
+                        "schema": {
+                            "$ref": "#/definitions/common.RESTBody-any"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "auth.Subject": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "unique_id": {
+                    "type": "string"
+                }
+            }
+        },
         "common.RESTBody-any": {
             "type": "object",
             "properties": {
@@ -107,9 +609,85 @@ const docTemplate = `{
                 }
             }
         },
+        "common.RESTBody-auth_Subject": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/auth.Subject"
+                },
+                "error": {
+                    "$ref": "#/definitions/common.RESTBodyError"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "common.RESTBody-health_Report": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/health.Report"
+                },
+                "error": {
+                    "$ref": "#/definitions/common.RESTBodyError"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "common.RESTBody-user_TokenDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.TokenDTO"
+                },
+                "error": {
+                    "$ref": "#/definitions/common.RESTBodyError"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "common.RESTBody-user_UserDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.UserDTO"
+                },
+                "error": {
+                    "$ref": "#/definitions/common.RESTBodyError"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "common.RESTBody-user_UserPageDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.UserPageDTO"
+                },
+                "error": {
+                    "$ref": "#/definitions/common.RESTBodyError"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "common.RESTBodyError": {
             "type": "object",
             "properties": {
+                "Violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/common.RESTFieldViolation"
+                    }
+                },
                 "code": {
                     "type": "integer"
                 },
@@ -118,25 +696,225 @@ const docTemplate = `{
                 }
             }
         },
-        "user.SignUpDTO": {
+        "common.RESTFieldViolation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "type": "boolean"
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/health.Status"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/health.Status"
+                }
+            }
+        },
+        "health.Status": {
+            "type": "string",
+            "enum": [
+                "up",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "StatusUp",
+                "StatusDown"
+            ]
+        },
+        "user.LoginDTO": {
             "type": "object",
+            "required": [
+                "password"
+            ],
             "properties": {
                 "email": {
                     "type": "string"
                 },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.RefreshTokenDTO": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.SignUpDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
                 "fullname": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "password": {
+                    "description": "bcrypt only use the first 72 bytes",
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "role": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                }
+            }
+        },
+        "user.TokenDTO": {
+            "type": "object",
+            "properties": {
+                "expire_at": {
+                    "type": "string"
+                },
+                "refresh_expire_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
                     "type": "string"
                 },
+                "user": {
+                    "$ref": "#/definitions/user.UserDTO"
+                }
+            }
+        },
+        "user.UpdateUserDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 254
+                },
+                "fullname": {
+                    "type": "string",
+                    "maxLength": 128
+                },
                 "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "role": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
+                }
+            }
+        },
+        "user.UserDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "fullname": {
                     "type": "string"
                 },
                 "role": {
                     "type": "string"
                 },
+                "status": {
+                    "type": "boolean"
+                },
+                "unique_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
                 "username": {
                     "type": "string"
                 }
             }
+        },
+        "user.UserPageDTO": {
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.UserDTO"
+                    }
+                }
+            }
         }
     }
 }`
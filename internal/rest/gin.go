@@ -2,6 +2,7 @@ package rest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -23,9 +24,21 @@ func newGinServer(cfg *config.ServiceConfig) *ginServer {
 	var s ginServer
 	s.cfg = cfg
 	ginEngine := gin.Default()
+	// Gin trusts every proxy by default, so any client could spoof its IP with X-Forwarded-For
+	if err := ginEngine.SetTrustedProxies(cfg.RestTrustedProxies); err != nil {
+		slog.Error("[SERVER] invalid trusted proxies, no proxy is trusted", "proxies", cfg.RestTrustedProxies, "error", err)
+		ginEngine.SetTrustedProxies(nil)
+	}
 	ginEngine.Use(otelgin.Middleware(cfg.ApplicationName))
 
 	s.router = ginEngine
+	s.srv = &http.Server{
+		Addr:         fmt.Sprintf(":%v", cfg.RestPort),
+		Handler:      otelhttp.NewHandler(ginEngine.Handler(), ""),
+		ReadTimeout:  time.Duration(cfg.RestReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(cfg.RestWriteTimeout) * time.Second,
+		IdleTimeout:  time.Duration(cfg.RestIdleTimeout) * time.Second,
+	}
 	return &s
 }
 
@@ -35,19 +48,10 @@ func (s *ginServer) RegisterRoutes(routesFn func(*gin.Engine)) {
 	}
 }
 
+// Run serves HTTP request until Shutdown is called, closed server is not reported as error
 func (s *ginServer) Run() error {
-	handler := otelhttp.NewHandler(s.router.Handler(), "")
-	listener := http.Server{
-		Addr:         fmt.Sprintf(":%v", s.cfg.RestPort),
-		Handler:      handler,
-		ReadTimeout:  time.Duration(s.cfg.RestReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(s.cfg.RestWriteTimeout) * time.Second,
-		IdleTimeout:  time.Duration(s.cfg.RestIdleTimeout) * time.Second,
-	}
-	s.srv = &listener
-
 	slog.Info("[SERVER] running...", "port", s.cfg.RestPort)
-	if err := listener.ListenAndServe(); err != nil {
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
@@ -55,11 +59,7 @@ func (s *ginServer) Run() error {
 }
 
 func (s *ginServer) Shutdown(ctx context.Context) error {
-	if s.srv == nil {
-		return http.ErrServerClosed
-	}
-
-	if err := s.srv.Shutdown(context.Background()); err != nil {
+	if err := s.srv.Shutdown(ctx); err != nil {
 		return err
 	}
 
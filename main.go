@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
-	"errors"
 	"log/slog"
+	"os"
 	"time"
 
 	"github.com/wahyurudiyan/go-boilerplate/app"
@@ -41,19 +41,20 @@ func main() {
 		panic(err)
 	}
 
-	// Run application gracefully
-	runApp := map[string]graceful.ExecCallback{
-		"REST": application.RestBootstrap(),
-		"GRPC": application.GRPCBootstrap(),
-		"OPENTELEMETRY": func(ctx context.Context) (graceful.ShutdownCallback, error) {
-			return func(ctx context.Context) error {
-				err = errors.Join(err, telemetryShutdown(ctx))
-				slog.Error("Service shutting down with error", "error", err)
-				return err
-			}, nil
-		},
+	// Run application gracefully, components are started after their dependencies
+	// and stopped before them
+	components := []graceful.Component{
+		{Name: app.ComponentTelemetry, Stop: telemetryShutdown},
+		application.ConfigWatcherComponent(),
+		application.RestBootstrap(),
+		application.GRPCBootstrap(),
+		application.OutboxRelayComponent(),
+		application.HealthComponent(),
 	}
-	if err := graceful.Run(parentCtx, time.Duration(10*time.Second), runApp); err != nil {
-		panic(err)
+	components = append(components, application.ResourceComponents()...)
+	components = append(components, application.ConfigSourceComponents()...)
+	if err := graceful.Run(parentCtx, time.Duration(10*time.Second), components...); err != nil {
+		slog.Error("Service shutting down with error", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,18 @@
+// Package migrations embeds the SQL migration files, so they are shipped within the binary.
+// Every dialect has its own migration set with the same versions.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+//go:embed postgres/*.sql mysql/*.sql
+var migrationFS embed.FS
+
+// ForDialect returns migration set of the dialect
+func ForDialect(dialect sql.IDialect) (fs.FS, error) {
+	return fs.Sub(migrationFS, dialect.Name())
+}
@@ -0,0 +1,13 @@
+CREATE TABLE outbox_events (
+    id VARCHAR(64) PRIMARY KEY,
+    event_type VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at DATETIME(6) NOT NULL,
+    published_at DATETIME(6) NULL
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(published_at, occurred_at, id);
+
+-- +migrate Down
+DROP TABLE outbox_events;
@@ -0,0 +1,21 @@
+-- Soft deleted user releases its email and username, they are only unique among active users.
+-- MySQL has no partial index, so the unique keys are on generated columns which are NULL
+-- for deleted user, and NULL never conflicts.
+ALTER TABLE users
+    ADD COLUMN active_email VARCHAR(255) AS (IF(deleted_at IS NULL, email, NULL)) VIRTUAL,
+    ADD COLUMN active_username VARCHAR(255) AS (IF(deleted_at IS NULL, username, NULL)) VIRTUAL;
+CREATE UNIQUE INDEX uq_users_active_email ON users(active_email);
+CREATE UNIQUE INDEX uq_users_active_username ON users(active_username);
+
+-- Lookups by email and username were served by the dropped unique keys
+CREATE INDEX idx_users_email ON users(email);
+CREATE INDEX idx_users_username ON users(username);
+ALTER TABLE users DROP INDEX email, DROP INDEX username;
+
+-- +migrate Down
+ALTER TABLE users ADD UNIQUE KEY email (email), ADD UNIQUE KEY username (username);
+DROP INDEX idx_users_username ON users;
+DROP INDEX idx_users_email ON users;
+DROP INDEX uq_users_active_username ON users;
+DROP INDEX uq_users_active_email ON users;
+ALTER TABLE users DROP COLUMN active_username, DROP COLUMN active_email;
@@ -19,3 +19,6 @@ CREATE INDEX idx_users_unique_id ON users(unique_id);
 CREATE INDEX idx_users_created_at ON users(created_at);
 CREATE INDEX idx_users_updated_at ON users(updated_at);
 CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+
+-- +migrate Down
+DROP TABLE users;
@@ -0,0 +1,14 @@
+CREATE TABLE outbox_events (
+    id VARCHAR(64) PRIMARY KEY,
+    event_type VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ
+);
+
+-- Relay only scans pending events
+CREATE INDEX idx_outbox_events_pending ON outbox_events(occurred_at, id) WHERE published_at IS NULL;
+
+-- +migrate Down
+DROP TABLE outbox_events;
@@ -0,0 +1,11 @@
+-- Soft deleted user releases its email and username, they are only unique among active users
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+ALTER TABLE users DROP CONSTRAINT users_username_key;
+CREATE UNIQUE INDEX uq_users_active_email ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_users_active_username ON users(username) WHERE deleted_at IS NULL;
+
+-- +migrate Down
+DROP INDEX uq_users_active_username;
+DROP INDEX uq_users_active_email;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,159 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+)
+
+func signTestToken(secretKey paseto.V4AsymmetricSecretKey, issuer string, expireAt time.Time) string {
+	token := paseto.NewToken()
+	token.SetIssuer(issuer)
+	token.SetSubject("user-1")
+	token.SetIssuedAt(time.Now())
+	token.SetNotBefore(time.Now())
+	token.SetExpiration(expireAt)
+	token.SetString(ClaimRole, "admin")
+	return token.V4Sign(secretKey, nil)
+}
+
+func newTestVerifier() (ITokenVerifier, paseto.V4AsymmetricSecretKey) {
+	secretKey := paseto.NewV4AsymmetricSecretKey()
+	verifier := NewPasetoVerifier(PasetoVerifierConfig{
+		Issuer:    "test-issuer",
+		PublicKey: secretKey.Public(),
+	})
+	return verifier, secretKey
+}
+
+func TestPasetoVerifier(t *testing.T) {
+	verifier, secretKey := newTestVerifier()
+	otherKey := paseto.NewV4AsymmetricSecretKey()
+
+	cases := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid token", signTestToken(secretKey, "test-issuer", time.Now().Add(time.Minute)), false},
+		{"empty token", "", true},
+		{"expired token", signTestToken(secretKey, "test-issuer", time.Now().Add(-time.Minute)), true},
+		{"wrong issuer", signTestToken(secretKey, "other-issuer", time.Now().Add(time.Minute)), true},
+		{"wrong key", signTestToken(otherKey, "test-issuer", time.Now().Add(time.Minute)), true},
+		{"malformed token", "v4.public.garbage", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			subject, err := verifier.Verify(context.Background(), tc.token)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if subject.UniqueId != "user-1" || subject.Role != "admin" {
+				t.Errorf("Unexpected subject: %+v", subject)
+			}
+		})
+	}
+}
+
+func TestGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier, secretKey := newTestVerifier()
+
+	router := gin.New()
+	router.GET("/protected", GinMiddleware(verifier), func(c *gin.Context) {
+		subject, ok := SubjectFromContext(c.Request.Context())
+		if !ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, subject.UniqueId)
+	})
+
+	validToken := signTestToken(secretKey, "test-issuer", time.Now().Add(time.Minute))
+	cases := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{"valid bearer token", "Bearer " + validToken, http.StatusOK},
+		{"missing header", "", http.StatusUnauthorized},
+		{"wrong scheme", "Basic " + validToken, http.StatusUnauthorized},
+		{"invalid token", "Bearer invalid", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("Expected status %d, got: %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	verifier, secretKey := newTestVerifier()
+	interceptor := UnaryServerInterceptor(verifier, "/test.Service/Public")
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		subject, _ := SubjectFromContext(ctx)
+		return subject.UniqueId, nil
+	}
+
+	validToken := signTestToken(secretKey, "test-issuer", time.Now().Add(time.Minute))
+	authCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+validToken))
+
+	res, err := interceptor(authCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Private"}, handler)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if res != "user-1" {
+		t.Errorf("Expected subject user-1 on context, got: %v", res)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Private"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got: %v", err)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Public"}, handler)
+	if err != nil {
+		t.Errorf("Expected public method to pass without token, got: %v", err)
+	}
+
+	res, err = interceptor(authCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Public"}, handler)
+	if err != nil || res != "user-1" {
+		t.Errorf("Expected public method to authenticate sent token, got: %v, %v", res, err)
+	}
+
+	invalidCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer invalid"))
+	_, err = interceptor(invalidCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Public"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for invalid token on public method, got: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package auth
+
+import "context"
+
+// Subject is the authenticated caller extracted from token
+type Subject struct {
+	UniqueId string `json:"unique_id"`
+	Role     string `json:"role"`
+}
+
+type subjectCtxKey struct{}
+
+// WithSubject returns copy of ctx that carry the authenticated subject
+func WithSubject(ctx context.Context, subject Subject) context.Context {
+	return context.WithValue(ctx, subjectCtxKey{}, subject)
+}
+
+// SubjectFromContext returns the authenticated subject, the boolean is false
+// when the request is not authenticated.
+func SubjectFromContext(ctx context.Context) (Subject, bool) {
+	subject, ok := ctx.Value(subjectCtxKey{}).(Subject)
+	return subject, ok
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// GinMiddleware rejects request without valid bearer token, the subject is
+// stored in request context and can be read with SubjectFromContext.
+func GinMiddleware(verifier ITokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c.GetHeader("Authorization"))
+		subject, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			err = ErrInvalidToken
+			if token == "" {
+				err = ErrMissingToken
+			}
+			c.AbortWithStatusJSON(errs.ToREST(err))
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithSubject(c.Request.Context(), subject))
+		c.Next()
+	}
+}
+
+// GinOptionalMiddleware authenticates request only when Authorization header is present,
+// so public endpoint can still act on behalf of the caller (e.g. admin creating user).
+func GinOptionalMiddleware(verifier ITokenVerifier) gin.HandlerFunc {
+	required := GinMiddleware(verifier)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
+}
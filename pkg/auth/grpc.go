@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor rejects call without valid bearer token in "authorization"
+// metadata, except for publicMethods (full method name e.g. /package.Service/Method).
+// Public methods are still authenticated when the caller sends a token.
+func UnaryServerInterceptor(verifier ITokenVerifier, publicMethods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		optional := slices.Contains(publicMethods, info.FullMethod)
+		ctx, err := authenticateGRPC(ctx, verifier, optional)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the stream version of UnaryServerInterceptor
+func StreamServerInterceptor(verifier ITokenVerifier, publicMethods ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		optional := slices.Contains(publicMethods, info.FullMethod)
+		ctx, err := authenticateGRPC(ss.Context(), verifier, optional)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticateGRPC(ctx context.Context, verifier ITokenVerifier, optional bool) (context.Context, error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	if optional && authorization == "" {
+		return ctx, nil
+	}
+
+	token := bearerToken(authorization)
+	subject, err := verifier.Verify(ctx, token)
+	if err != nil {
+		if token == "" {
+			return ctx, errs.GRPCError(ErrMissingToken)
+		}
+		return ctx, errs.GRPCError(ErrInvalidToken)
+	}
+
+	return WithSubject(ctx, subject), nil
+}
+
+// authenticatedStream override stream context so handler can read the subject
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// ClaimRole is the PASETO claim key that carry the user role
+const ClaimRole = "role"
+
+var (
+	ErrMissingToken = errs.Unauthorized("authorization token is missing")
+	ErrInvalidToken = errs.Unauthorized("authorization token is invalid or expired")
+)
+
+type ITokenVerifier interface {
+	Verify(ctx context.Context, token string) (Subject, error)
+}
+
+type PasetoVerifierConfig struct {
+	Issuer    string
+	PublicKey paseto.V4AsymmetricPublicKey
+}
+
+type pasetoVerifier struct {
+	cfg    PasetoVerifierConfig
+	parser paseto.Parser
+}
+
+// NewPasetoVerifier creates verifier for v4 public PASETO, the expiration,
+// not-before and issued-at claims are always checked.
+func NewPasetoVerifier(cfg PasetoVerifierConfig) ITokenVerifier {
+	parser := paseto.NewParser()
+	if cfg.Issuer != "" {
+		parser.AddRule(paseto.IssuedBy(cfg.Issuer))
+	}
+
+	return &pasetoVerifier{
+		cfg:    cfg,
+		parser: parser,
+	}
+}
+
+func (p *pasetoVerifier) Verify(ctx context.Context, token string) (Subject, error) {
+	if token == "" {
+		return Subject{}, ErrMissingToken
+	}
+
+	parsed, err := p.parser.ParseV4Public(p.cfg.PublicKey, token, nil)
+	if err != nil {
+		return Subject{}, errors.Join(ErrInvalidToken, err)
+	}
+
+	uniqueId, err := parsed.GetSubject()
+	if err != nil || uniqueId == "" {
+		return Subject{}, ErrInvalidToken
+	}
+
+	// role claim is optional, user without role is treated as anonymous role
+	role, _ := parsed.GetString(ClaimRole)
+
+	return Subject{
+		UniqueId: uniqueId,
+		Role:     role,
+	}, nil
+}
+
+// bearerToken extract token from "Bearer <token>" authorization value
+func bearerToken(authorization string) string {
+	scheme, token, found := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
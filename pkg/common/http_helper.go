@@ -9,8 +9,16 @@ type RESTBody[T any] struct {
 }
 
 type RESTBodyError struct {
-	Code   int
-	Reason string
+	Code       int
+	Reason     string
+	Violations []RESTFieldViolation `json:"Violations,omitempty"`
+}
+
+// RESTFieldViolation points to the request field that failed validation
+type RESTFieldViolation struct {
+	Field   string
+	Rule    string
+	Message string
 }
 
 func BindJSON[T any](c *gin.Context) (RESTBody[T], error) {
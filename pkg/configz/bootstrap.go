@@ -0,0 +1,139 @@
+package configz
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	awsssm "github.com/PaddleHQ/go-aws-ssm"
+)
+
+// Bootstrap environment variables, they tell where the configuration is loaded from
+const (
+	EnvConfigSources       = "CONFIG_SOURCES" // comma separated sources, default is "env,flags"
+	EnvConfigPrefix        = "CONFIG_PREFIX"  // key prefix stripped from every source, default is "user"
+	EnvConfigFile          = "CONFIG_FILE"    // config file of file source, default is "config.yaml"
+	EnvConfigDotenv        = "CONFIG_DOTENV"  // dotenv file of dotenv source, default is ".env"
+	EnvConfigSSMPath       = "CONFIG_SSM_PATH"
+	EnvConfigWatchInterval = "CONFIG_WATCH_INTERVAL" // polling interval of ssm and vault sources, default is 30s
+	EnvVaultAddr           = "VAULT_ADDR"
+	EnvVaultPath           = "VAULT_PATH"    // e.g. /v1/kv/data/go-boilerplate
+	EnvVaultTimeout        = "VAULT_TIMEOUT" // default is 30s
+	EnvVaultNamespace      = "VAULT_NAMESPACE"
+)
+
+// Bootstrap environment variables of Vault authentication, see VaultConfig
+const (
+	EnvVaultAuthMethod          = "VAULT_AUTH_METHOD" // token (default), token_file, approle or kubernetes
+	EnvVaultAuthMount           = "VAULT_AUTH_MOUNT"  // default is the method name
+	EnvVaultToken               = "VAULT_TOKEN"
+	EnvVaultTokenFile           = "VAULT_TOKEN_FILE"
+	EnvVaultRoleID              = "VAULT_ROLE_ID"
+	EnvVaultSecretID            = "VAULT_SECRET_ID"
+	EnvVaultKubernetesRole      = "VAULT_KUBERNETES_ROLE"
+	EnvVaultKubernetesTokenFile = "VAULT_KUBERNETES_TOKEN_FILE"
+)
+
+const (
+	defaultConfigSources = SourceEnv + "," + SourceFlags
+	defaultConfigPrefix  = "user"
+	defaultConfigFile    = "config.yaml"
+	defaultConfigDotenv  = ".env"
+	defaultVaultTimeout  = 30 * time.Second
+)
+
+// NewLoaderFromEnv returns loader of the sources listed in CONFIG_SOURCES, they are
+// merged in the precedence order regardless of the listing order. args are the command
+// line arguments read by flags source, without the program name.
+func NewLoaderFromEnv(args []string) (*Loader, error) {
+	names := strings.Split(getenv(EnvConfigSources, defaultConfigSources), ",")
+	for i, name := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(sourceOrder, names[i]) || names[i] == SourceDefault {
+			return nil, fmt.Errorf("unknown configuration source %q of %s", name, EnvConfigSources)
+		}
+	}
+
+	var sources []ISource
+	for _, name := range sourceOrder {
+		if !slices.Contains(names, name) {
+			continue
+		}
+
+		source, err := newSourceFromEnv(name, args)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	return NewLoader(getenv(EnvConfigPrefix, defaultConfigPrefix), sources...), nil
+}
+
+// WatchConfigFromEnv returns watcher config of CONFIG_WATCH_INTERVAL
+func WatchConfigFromEnv() (WatchConfig, error) {
+	interval, err := time.ParseDuration(getenv(EnvConfigWatchInterval, defaultWatchInterval.String()))
+	if err != nil {
+		return WatchConfig{}, fmt.Errorf("invalid %s: %w", EnvConfigWatchInterval, err)
+	}
+	return WatchConfig{Interval: interval}, nil
+}
+
+func newSourceFromEnv(name string, args []string) (ISource, error) {
+	switch name {
+	case SourceFile:
+		return NewFileSource(getenv(EnvConfigFile, defaultConfigFile)), nil
+	case SourceDotenv:
+		return NewDotenvSource(getenv(EnvConfigDotenv, defaultConfigDotenv)), nil
+	case SourceEnv:
+		return NewEnvSource(), nil
+	case SourceFlags:
+		return NewFlagSource(args), nil
+	case SourceSSM:
+		path := os.Getenv(EnvConfigSSMPath)
+		if path == "" {
+			return nil, fmt.Errorf("%s is required by ssm source", EnvConfigSSMPath)
+		}
+		store, err := awsssm.NewParameterStore()
+		if err != nil {
+			return nil, err
+		}
+		return NewSSMSource(store, path), nil
+	case SourceVault:
+		address, path := os.Getenv(EnvVaultAddr), os.Getenv(EnvVaultPath)
+		if address == "" || path == "" {
+			return nil, fmt.Errorf("%s and %s are required by vault source", EnvVaultAddr, EnvVaultPath)
+		}
+		timeout, err := time.ParseDuration(getenv(EnvVaultTimeout, defaultVaultTimeout.String()))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvVaultTimeout, err)
+		}
+		vault, err := NewVault(&VaultConfig{
+			Address:             address,
+			Namespace:           os.Getenv(EnvVaultNamespace),
+			Timeout:             timeout,
+			AuthMethod:          os.Getenv(EnvVaultAuthMethod),
+			AuthMount:           os.Getenv(EnvVaultAuthMount),
+			Token:               os.Getenv(EnvVaultToken),
+			TokenFile:           os.Getenv(EnvVaultTokenFile),
+			RoleID:              os.Getenv(EnvVaultRoleID),
+			SecretID:            os.Getenv(EnvVaultSecretID),
+			KubernetesRole:      os.Getenv(EnvVaultKubernetesRole),
+			KubernetesTokenFile: os.Getenv(EnvVaultKubernetesTokenFile),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return NewVaultSource(vault, path), nil
+	}
+	return nil, fmt.Errorf("unknown configuration source %q", name)
+}
+
+func getenv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
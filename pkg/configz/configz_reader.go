@@ -1,43 +1,71 @@
 package configz
 
 import (
-	"bytes"
 	"encoding/json"
+	"reflect"
 	"strings"
+	"time"
 
-	"github.com/go-playground/assert/v2"
-	"github.com/spf13/viper"
+	"github.com/go-viper/mapstructure/v2"
 )
 
+// decode decodes configMap into out with a decoder of its own, so concurrent loads do
+// not share state. configMap is not modified, prefixed keys are handled by normalizeKeys.
 func decode(prefix string, configMap map[string]any, out any) error {
-	if !assert.IsEqual(prefix, "") {
-		for k, val := range configMap {
-			oldKey := strings.ToUpper(k)
-			prefix := strings.ToUpper(prefix + "_")
-			if strings.HasPrefix(oldKey, prefix) {
-				newKey := strings.TrimPrefix(oldKey, prefix)
-				configMap[newKey] = val
-				delete(configMap, k)
-			}
-		}
-	}
-
-	viper.SetConfigType("json")
-	configByte, err := json.Marshal(configMap)
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           out,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			stringToCollectionHook,
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToTimeHookFunc(time.RFC3339),
+			mapstructure.TextUnmarshallerHookFunc(),
+		),
+	})
 	if err != nil {
 		return err
 	}
 
-	bufConfig := bytes.NewReader(configByte)
-	err = viper.ReadConfig(bufConfig)
-	if err != nil {
-		return err
-	}
+	return decoder.Decode(normalizeKeys(prefix, configMap))
+}
 
-	err = viper.Unmarshal(&out)
-	if err != nil {
-		return err
+// stringToCollectionHook decodes JSON object or array given as string (e.g. environment
+// variable) into map, struct or slice field, slice also accepts comma separated values
+func stringToCollectionHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
 	}
 
-	return nil
+	value := strings.TrimSpace(reflect.ValueOf(data).String())
+	switch to.Kind() {
+	case reflect.Map, reflect.Struct:
+		if !strings.HasPrefix(value, "{") || to == reflect.TypeOf(time.Time{}) {
+			return data, nil
+		}
+		var object map[string]any
+		if err := json.Unmarshal([]byte(value), &object); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case reflect.Slice:
+		if to.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+		if !strings.HasPrefix(value, "[") {
+			if value == "" {
+				return []any{}, nil
+			}
+			items := strings.Split(value, ",")
+			for i := range items {
+				items[i] = strings.TrimSpace(items[i])
+			}
+			return items, nil
+		}
+		var array []any
+		if err := json.Unmarshal([]byte(value), &array); err != nil {
+			return nil, err
+		}
+		return array, nil
+	}
+	return data, nil
 }
@@ -1,9 +1,11 @@
 package configz
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
-	"testing"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -28,9 +30,26 @@ type TestConfig struct {
 	AppName     string `mapstructure:"APP_NAME"`
 }
 
-func TestConfigz(t *testing.T) {
-	RegisterFailHandler(Fail)
-	RunSpecs(t, "Configz Suite")
+type decodeServer struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+}
+
+type decodeShared struct {
+	Region string `mapstructure:"REGION"`
+}
+
+// Test struct untuk decode
+type DecodeConfig struct {
+	Name      string            `mapstructure:"NAME"`
+	Timeout   time.Duration     `mapstructure:"TIMEOUT"`
+	StartedAt time.Time         `mapstructure:"STARTED_AT"`
+	Hosts     []string          `mapstructure:"HOSTS"`
+	Ports     []int             `mapstructure:"PORTS"`
+	Server    decodeServer      `mapstructure:"SERVER"`
+	Replicas  []decodeServer    `mapstructure:"REPLICAS"`
+	Labels    map[string]string `mapstructure:"LABELS"`
+	Shared    decodeShared      `mapstructure:",squash"`
 }
 
 var _ = Describe("Configz", func() {
@@ -271,3 +290,90 @@ var _ = Describe("Configz with Dependency Injection (Suggested Refactor)", func(
 		})
 	})
 })
+
+var _ = Describe("decode", func() {
+	startedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	DescribeTable("should decode config map",
+		func(prefix string, configMap map[string]any, expected DecodeConfig) {
+			snapshot := fmt.Sprint(configMap)
+
+			var config DecodeConfig
+			Expect(decode(prefix, configMap, &config)).To(Succeed())
+			Expect(config).To(Equal(expected))
+			Expect(fmt.Sprint(configMap)).To(Equal(snapshot), "config map must not be modified")
+		},
+		Entry("case insensitive keys", "",
+			map[string]any{"name": "service-user", "Region": "ap-southeast-3"},
+			DecodeConfig{Name: "service-user", Shared: decodeShared{Region: "ap-southeast-3"}}),
+		Entry("prefix is stripped", "user",
+			map[string]any{"USER_NAME": "service-user", "user_region": "ap-southeast-3"},
+			DecodeConfig{Name: "service-user", Shared: decodeShared{Region: "ap-southeast-3"}}),
+		Entry("prefixed key wins over unprefixed key", "user",
+			map[string]any{"NAME": "unprefixed", "USER_NAME": "prefixed"},
+			DecodeConfig{Name: "prefixed"}),
+		Entry("prefix alone is not stripped", "user",
+			map[string]any{"USER_": "ignored", "NAME": "service-user"},
+			DecodeConfig{Name: "service-user"}),
+		Entry("duration and time from string", "",
+			map[string]any{"TIMEOUT": "1m30s", "STARTED_AT": "2026-01-02T03:04:05Z"},
+			DecodeConfig{Timeout: 90 * time.Second, StartedAt: startedAt}),
+		Entry("slice from comma separated string", "",
+			map[string]any{"HOSTS": "a:1,b:2", "PORTS": "80,443"},
+			DecodeConfig{Hosts: []string{"a:1", "b:2"}, Ports: []int{80, 443}}),
+		Entry("slice from JSON string", "",
+			map[string]any{"HOSTS": `["a:1","b:2"]`, "PORTS": "[80, 443]"},
+			DecodeConfig{Hosts: []string{"a:1", "b:2"}, Ports: []int{80, 443}}),
+		Entry("slice from list", "",
+			map[string]any{"HOSTS": []any{"a:1", "b:2"}, "PORTS": []any{"80", 443}},
+			DecodeConfig{Hosts: []string{"a:1", "b:2"}, Ports: []int{80, 443}}),
+		Entry("nested struct from map", "",
+			map[string]any{"SERVER": map[string]any{"HOST": "localhost", "port": "8080"}},
+			DecodeConfig{Server: decodeServer{Host: "localhost", Port: 8080}}),
+		Entry("nested struct from JSON string", "",
+			map[string]any{"SERVER": `{"host":"localhost","port":8080}`},
+			DecodeConfig{Server: decodeServer{Host: "localhost", Port: 8080}}),
+		Entry("slice of struct", "",
+			map[string]any{"REPLICAS": `[{"host":"a","port":1},{"host":"b","port":2}]`},
+			DecodeConfig{Replicas: []decodeServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}}}),
+		Entry("map from map and JSON string", "",
+			map[string]any{"LABELS": `{"team":"user","tier":"1"}`},
+			DecodeConfig{Labels: map[string]string{"team": "user", "tier": "1"}}),
+	)
+
+	DescribeTable("should return error of invalid value",
+		func(configMap map[string]any) {
+			var config DecodeConfig
+			Expect(decode("", configMap, &config)).ToNot(Succeed())
+		},
+		Entry("invalid duration", map[string]any{"TIMEOUT": "soon"}),
+		Entry("invalid time", map[string]any{"STARTED_AT": "yesterday"}),
+		Entry("invalid JSON object", map[string]any{"SERVER": `{"host":`}),
+		Entry("invalid number", map[string]any{"PORTS": "80,https"}),
+	)
+
+	It("should decode into pointer of nil pointer", func() {
+		var config *DecodeConfig
+		Expect(decode("", map[string]any{"NAME": "service-user"}, &config)).To(Succeed())
+		Expect(config).ToNot(BeNil())
+		Expect(config.Name).To(Equal("service-user"))
+	})
+
+	It("should not share state between concurrent decodes", func() {
+		var wg sync.WaitGroup
+		configs := make([]DecodeConfig, 20)
+		for i := range configs {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(decode("", map[string]any{"NAME": fmt.Sprint(i)}, &configs[i])).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		for i, config := range configs {
+			Expect(config).To(Equal(DecodeConfig{Name: fmt.Sprint(i)}))
+		}
+	})
+})
@@ -0,0 +1,153 @@
+package configz
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+const redacted = "[REDACTED]"
+
+// secretSuffixes marks keys which value is redacted from the effective config, field can also be
+// marked explicitly with `secret:"true"` tag
+var secretSuffixes = []string{"PASSWORD", "SECRET", "SECRET_KEY", "TOKEN", "PRIVATE_KEY"}
+
+// EffectiveValue is the effective value of a config key and the source which supplied it
+type EffectiveValue struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// EffectiveConfig is the effective configuration with secrets redacted, ordered as the fields
+// of the config struct
+type EffectiveConfig []EffectiveValue
+
+// Source returns the source which supplied key, empty when key is unknown
+func (c EffectiveConfig) Source(key string) string {
+	key = strings.ToUpper(key)
+	for _, entry := range c {
+		if entry.Key == key {
+			return entry.Source
+		}
+	}
+	return ""
+}
+
+// WriteTo prints the effective config as table of key, value and source
+func (c EffectiveConfig) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	tw := tabwriter.NewWriter(counter, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, entry := range c {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Key, entry.Value, entry.Source)
+	}
+	err := tw.Flush()
+	return counter.n, err
+}
+
+// LogValue groups the effective config by key, so it can be logged as a single attribute
+func (c EffectiveConfig) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(c))
+	for _, entry := range c {
+		attrs = append(attrs, slog.String(entry.Key, fmt.Sprintf("%s (%s)", entry.Value, entry.Source)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// field is a config key of the struct, index is the path of squashed struct fields
+type field struct {
+	key          string
+	index        []int
+	secret       bool
+	reloadable   bool
+	defaultValue string
+	hasDefault   bool
+}
+
+// fieldsOf returns config keys of struct type t, fields of squashed struct are flattened
+// and inherit `reload` tag of the squashed field
+func fieldsOf(t reflect.Type, index []int) []field {
+	return fieldsWithReload(t, index, false)
+}
+
+func fieldsWithReload(t reflect.Type, index []int, reloadable bool) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(structField.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldReloadable := reloadable
+		if reload, ok := structField.Tag.Lookup("reload"); ok {
+			fieldReloadable = reload == "true"
+		}
+		if strings.Contains(opts, "squash") && structField.Type.Kind() == reflect.Struct {
+			fields = append(fields, fieldsWithReload(structField.Type, fieldIndex, fieldReloadable)...)
+			continue
+		}
+
+		if name == "" {
+			name = structField.Name
+		}
+		key := strings.ToUpper(name)
+		defaultValue, hasDefault := structField.Tag.Lookup("default")
+		fields = append(fields, field{
+			key:          key,
+			index:        fieldIndex,
+			secret:       structField.Tag.Get("secret") == "true" || isSecretKey(key),
+			reloadable:   fieldReloadable,
+			defaultValue: defaultValue,
+			hasDefault:   hasDefault,
+		})
+	}
+	return fields
+}
+
+func isSecretKey(key string) bool {
+	for _, suffix := range secretSuffixes {
+		if key == suffix || strings.HasSuffix(key, "_"+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func newEffectiveConfig(val reflect.Value, provenance map[string]string) EffectiveConfig {
+	fields := fieldsOf(val.Type(), nil)
+	effective := make(EffectiveConfig, 0, len(fields))
+	for _, f := range fields {
+		source, ok := provenance[f.key]
+		if !ok {
+			source = SourceDefault
+		}
+
+		value := fmt.Sprint(val.FieldByIndex(f.index).Interface())
+		if f.secret && value != "" {
+			value = redacted
+		}
+		effective = append(effective, EffectiveValue{Key: f.key, Value: value, Source: source})
+	}
+	return effective
+}
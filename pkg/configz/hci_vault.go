@@ -2,57 +2,314 @@ package configz
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/vault-client-go"
+	"github.com/hashicorp/vault-client-go/schema"
+)
+
+// Authentication methods of VaultConfig.AuthMethod
+const (
+	VaultAuthToken      = "token"
+	VaultAuthTokenFile  = "token_file"
+	VaultAuthAppRole    = "approle"
+	VaultAuthKubernetes = "kubernetes"
+)
+
+const (
+	defaultKubernetesTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// vaultRetryInterval is the delay before retrying failed token renewal
+	vaultRetryInterval = 5 * time.Second
 )
 
 type VaultConfig struct {
-	Token   string
-	Prefix  string
-	Address string
-	Timeout time.Duration
+	Token     string
+	Prefix    string
+	Address   string
+	Namespace string // Vault Enterprise namespace, empty is the root namespace
+	Timeout   time.Duration
+
+	// AuthMethod is token (default), token_file, approle or kubernetes
+	AuthMethod string
+	// AuthMount is mount path of approle and kubernetes method, default is the method name
+	AuthMount string
+	// TokenFile is read by token_file method, e.g. sink file of Vault Agent
+	TokenFile string
+	// RoleID and SecretID log in with approle method
+	RoleID   string
+	SecretID string
+	// KubernetesRole logs in with service account JWT read from KubernetesTokenFile,
+	// default is the token mounted into the pod
+	KubernetesRole      string
+	KubernetesTokenFile string
 
 	client *vault.Client
+	lease  tokenLease
+}
+
+// tokenLease is the validity of the token used by the client
+type tokenLease struct {
+	ttl       time.Duration // zero means the token never expires, e.g. root token
+	renewable bool
 }
 
 type IVaultConfig interface {
 	LoadConfig(ctx context.Context, path string, out any) error
+	Read(ctx context.Context, path string) (map[string]any, error)
+	Version(ctx context.Context, path string) (string, error)
+	Ping(ctx context.Context) error
+	RenewToken(ctx context.Context) error
 }
 
-func NewVault(vc *VaultConfig) IVaultConfig {
+// NewVault returns Vault client authenticated with vc.AuthMethod, the token is kept valid
+// by RenewToken
+func NewVault(vc *VaultConfig) (IVaultConfig, error) {
 	v, err := vault.New(
 		vault.WithAddress(vc.Address),
 		vault.WithRequestTimeout(vc.Timeout),
 	)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	if err := v.SetToken(vc.Token); err != nil {
-		panic(err)
+	if vc.Namespace != "" {
+		if err := v.SetNamespace(vc.Namespace); err != nil {
+			return nil, err
+		}
 	}
 
 	vc.client = v
+	if vc.lease, err = vc.login(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to authenticate to vault with %s method: %w", vc.authMethod(), err)
+	}
 
-	return vc
+	return vc, nil
 }
 
 func (v *VaultConfig) LoadConfig(ctx context.Context, path string, out any) error {
-	secret, err := v.client.Read(ctx, path)
+	secretData, err := v.Read(ctx, path)
 	if err != nil {
 		return err
 	}
 
+	if err := decode(v.Prefix, secretData, out); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Read returns data of KV v2 secret at path
+func (v *VaultConfig) Read(ctx context.Context, path string) (map[string]any, error) {
+	secret, err := v.client.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
 	secretData, ok := secret.Data["data"].(map[string]any)
 	if !ok {
-		return fmt.Errorf("no secret for '%s'", path)
+		return nil, fmt.Errorf("no secret for '%s'", path)
 	}
 
-	if err := decode(v.Prefix, secretData, out); err != nil {
-		return err
+	return secretData, nil
+}
+
+// Version returns current_version of KV v2 secret at data path (e.g. /v1/kv/data/app),
+// it is read from the metadata path so the secret itself is not fetched
+func (v *VaultConfig) Version(ctx context.Context, path string) (string, error) {
+	mount, secretPath, found := strings.Cut(path, "/data/")
+	if !found {
+		return "", fmt.Errorf("'%s' is not a KV v2 data path", path)
+	}
+
+	metadata, err := v.client.Read(ctx, mount+"/metadata/"+secretPath)
+	if err != nil {
+		return "", err
 	}
 
+	version, ok := metadata.Data["current_version"]
+	if !ok {
+		return "", fmt.Errorf("no metadata for '%s'", path)
+	}
+	return fmt.Sprint(version), nil
+}
+
+// Ping reads Vault health status, sealed or uninitialized Vault is reported as error
+func (v *VaultConfig) Ping(ctx context.Context) error {
+	_, err := v.client.System.ReadHealthStatus(ctx)
+	return err
+}
+
+// RenewToken keeps the token valid until ctx is cancelled. The token is renewed after two
+// thirds of its TTL, and the auth method logs in again when the token is not renewable or
+// is about to reach its max TTL. Token which never expires is not renewed, static token
+// which is not renewable is left to expire with a single warning.
+func (v *VaultConfig) RenewToken(ctx context.Context) error {
+	lease := v.lease
+	wait := lease.ttl * 2 / 3
+	for lease.ttl > 0 {
+		if !lease.renewable && !v.canLogin() {
+			slog.WarnContext(ctx, "[VAULT] token is not renewable, replace it before it expires", "method", v.authMethod(), "ttl", lease.ttl)
+			break
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		next, err := v.refresh(ctx, lease)
+		if err != nil {
+			slog.WarnContext(ctx, "[VAULT] failed to renew token, retrying", "method", v.authMethod(), "error", err)
+			wait = vaultRetryInterval
+			continue
+		}
+		lease, wait = next, next.ttl*2/3
+		slog.DebugContext(ctx, "[VAULT] token renewed", "method", v.authMethod(), "ttl", lease.ttl)
+	}
+
+	<-ctx.Done()
 	return nil
 }
+
+// refresh renews the token, or logs in again when the method is able to
+func (v *VaultConfig) refresh(ctx context.Context, lease tokenLease) (tokenLease, error) {
+	canLogin := v.canLogin()
+	if lease.renewable {
+		resp, err := v.client.Auth.TokenRenewSelf(ctx, schema.TokenRenewSelfRequest{})
+		if err == nil && resp.Auth == nil {
+			err = errors.New("renewal response has no auth")
+		}
+		if err == nil {
+			renewed := leaseOf(resp.Auth)
+			// Shorter TTL than before means the token is capped by its max TTL
+			if renewed.ttl >= lease.ttl || !canLogin {
+				return renewed, nil
+			}
+		} else if !canLogin {
+			return lease, err
+		}
+	}
+
+	if !canLogin {
+		return lease, errors.New("token is not renewable")
+	}
+	return v.login(ctx)
+}
+
+// login sets token of the auth method to the client and returns its lease
+func (v *VaultConfig) login(ctx context.Context) (tokenLease, error) {
+	var (
+		resp *vault.Response[map[string]any]
+		err  error
+	)
+	switch v.authMethod() {
+	case VaultAuthToken:
+		return v.useToken(ctx, v.Token)
+	case VaultAuthTokenFile:
+		token, err := readSecretFile(v.TokenFile)
+		if err != nil {
+			return tokenLease{}, err
+		}
+		return v.useToken(ctx, token)
+	case VaultAuthAppRole:
+		resp, err = v.client.Auth.AppRoleLogin(ctx, schema.AppRoleLoginRequest{
+			RoleId:   v.RoleID,
+			SecretId: v.SecretID,
+		}, vault.WithMountPath(v.authMount()))
+	case VaultAuthKubernetes:
+		tokenFile := v.KubernetesTokenFile
+		if tokenFile == "" {
+			tokenFile = defaultKubernetesTokenFile
+		}
+		jwt, jwtErr := readSecretFile(tokenFile)
+		if jwtErr != nil {
+			return tokenLease{}, jwtErr
+		}
+		resp, err = v.client.Auth.KubernetesLogin(ctx, schema.KubernetesLoginRequest{
+			Jwt:  jwt,
+			Role: v.KubernetesRole,
+		}, vault.WithMountPath(v.authMount()))
+	default:
+		return tokenLease{}, fmt.Errorf("unknown vault auth method %q", v.AuthMethod)
+	}
+	if err != nil {
+		return tokenLease{}, err
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return tokenLease{}, errors.New("login response has no client token")
+	}
+
+	if err := v.client.SetToken(resp.Auth.ClientToken); err != nil {
+		return tokenLease{}, err
+	}
+	return leaseOf(resp.Auth), nil
+}
+
+// useToken sets token to the client and looks up its lease
+func (v *VaultConfig) useToken(ctx context.Context, token string) (tokenLease, error) {
+	if token == "" {
+		return tokenLease{}, errors.New("token cannot be empty")
+	}
+	if err := v.client.SetToken(token); err != nil {
+		return tokenLease{}, err
+	}
+
+	resp, err := v.client.Auth.TokenLookUpSelf(ctx)
+	if err != nil {
+		return tokenLease{}, err
+	}
+
+	ttl, err := strconv.ParseInt(fmt.Sprint(resp.Data["ttl"]), 10, 64)
+	if err != nil {
+		return tokenLease{}, fmt.Errorf("invalid token ttl: %w", err)
+	}
+	renewable, _ := resp.Data["renewable"].(bool)
+	return tokenLease{ttl: time.Duration(ttl) * time.Second, renewable: renewable}, nil
+}
+
+func (v *VaultConfig) authMethod() string {
+	if v.AuthMethod == "" {
+		return VaultAuthToken
+	}
+	return v.AuthMethod
+}
+
+// canLogin reports whether the auth method is able to get a new token, static token cannot
+func (v *VaultConfig) canLogin() bool {
+	return v.authMethod() != VaultAuthToken
+}
+
+func (v *VaultConfig) authMount() string {
+	if v.AuthMount == "" {
+		return v.authMethod()
+	}
+	return v.AuthMount
+}
+
+func leaseOf(auth *vault.ResponseAuth) tokenLease {
+	return tokenLease{ttl: time.Duration(auth.LeaseDuration) * time.Second, renewable: auth.Renewable}
+}
+
+// readSecretFile reads token or JWT file, it is read on every login because the file
+// is rotated by its writer (e.g. kubelet or Vault Agent)
+func readSecretFile(filename string) (string, error) {
+	if filename == "" {
+		return "", errors.New("token file cannot be empty")
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
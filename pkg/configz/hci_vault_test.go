@@ -0,0 +1,307 @@
+package configz_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/configz"
+)
+
+const vaultSecretPath = "/v1/kv/data/go-boilerplate"
+
+// vaultEmulator serves the Vault endpoints used by VaultConfig, issued tokens expire
+// after ttl seconds and renewal extends them by renewTTL seconds
+type vaultEmulator struct {
+	mu        sync.Mutex
+	namespace string
+	ttl       int
+	renewTTL  int
+	renewable bool
+	tokens    map[string]time.Time // zero expiry never expires
+	issued    int
+	logins    int
+	renewals  int
+}
+
+func newVaultEmulator() *vaultEmulator {
+	return &vaultEmulator{
+		namespace: "team-a",
+		ttl:       3600,
+		renewTTL:  3600,
+		renewable: true,
+		tokens:    map[string]time.Time{"static-token": {}},
+	}
+}
+
+func (e *vaultEmulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if r.Header.Get("X-Vault-Namespace") != e.namespace {
+		writeVaultError(w, http.StatusNotFound, "namespace not found")
+		return
+	}
+
+	var body map[string]string
+	if r.Method == http.MethodPost {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "POST /v1/auth/approle/login":
+		if body["role_id"] != "role-id" || body["secret_id"] != "secret-id" {
+			writeVaultError(w, http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		e.login(w)
+		return
+	case "POST /v1/auth/k8s/login":
+		if body["jwt"] != "service-account-jwt" || body["role"] != "service-user" {
+			writeVaultError(w, http.StatusForbidden, "permission denied")
+			return
+		}
+		e.login(w)
+		return
+	}
+
+	token := r.Header.Get("X-Vault-Token")
+	expiry, ok := e.tokens[token]
+	if !ok || (!expiry.IsZero() && time.Now().After(expiry)) {
+		writeVaultError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /v1/auth/token/lookup-self":
+		ttl := 0
+		if !expiry.IsZero() {
+			ttl = int(time.Until(expiry).Seconds())
+		}
+		writeVaultJSON(w, map[string]any{"data": map[string]any{"ttl": ttl, "renewable": !expiry.IsZero() && e.renewable}})
+	case "POST /v1/auth/token/renew-self":
+		e.renewals++
+		e.tokens[token] = time.Now().Add(time.Duration(e.renewTTL) * time.Second)
+		writeVaultJSON(w, map[string]any{"data": nil, "auth": map[string]any{
+			"client_token": token, "lease_duration": e.renewTTL, "renewable": e.renewable,
+		}})
+	case "GET " + vaultSecretPath:
+		writeVaultJSON(w, map[string]any{"data": map[string]any{"data": map[string]any{"APP_NAME": "from-vault"}}})
+	default:
+		writeVaultError(w, http.StatusNotFound, "unsupported path")
+	}
+}
+
+func (e *vaultEmulator) login(w http.ResponseWriter) {
+	e.logins++
+	e.issued++
+	token := fmt.Sprintf("token-%d", e.issued)
+	e.tokens[token] = time.Now().Add(time.Duration(e.ttl) * time.Second)
+	writeVaultJSON(w, map[string]any{"data": nil, "auth": map[string]any{
+		"client_token": token, "lease_duration": e.ttl, "renewable": e.renewable,
+	}})
+}
+
+func (e *vaultEmulator) counts() (logins, renewals int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.logins, e.renewals
+}
+
+// logBuffer collects log records written concurrently by the code under test
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func writeVaultJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeVaultError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{message}})
+}
+
+var _ = Describe("Vault", func() {
+	var (
+		emulator *vaultEmulator
+		server   *httptest.Server
+		tempDir  string
+	)
+
+	BeforeEach(func() {
+		emulator = newVaultEmulator()
+		server = httptest.NewServer(emulator)
+		DeferCleanup(server.Close)
+		tempDir = GinkgoT().TempDir()
+	})
+
+	writeSecretFile := func(name, content string) string {
+		filename := filepath.Join(tempDir, name)
+		Expect(os.WriteFile(filename, []byte(content+"\n"), 0600)).To(Succeed())
+		return filename
+	}
+
+	newConfig := func(method string) *VaultConfig {
+		vc := &VaultConfig{
+			Address:        server.URL,
+			Namespace:      "team-a",
+			Timeout:        time.Second,
+			AuthMethod:     method,
+			Token:          "static-token",
+			RoleID:         "role-id",
+			SecretID:       "secret-id",
+			KubernetesRole: "service-user",
+		}
+		switch method {
+		case VaultAuthTokenFile:
+			vc.TokenFile = writeSecretFile("token", "static-token")
+		case VaultAuthKubernetes:
+			vc.AuthMount = "k8s"
+			vc.KubernetesTokenFile = writeSecretFile("jwt", "service-account-jwt")
+		}
+		return vc
+	}
+
+	DescribeTable("should read secret after authenticating",
+		func(method string) {
+			vault, err := NewVault(newConfig(method))
+			Expect(err).ToNot(HaveOccurred())
+
+			secret, err := vault.Read(context.Background(), vaultSecretPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret).To(HaveKeyWithValue("APP_NAME", "from-vault"))
+		},
+		Entry("default method", ""),
+		Entry("token", VaultAuthToken),
+		Entry("token file", VaultAuthTokenFile),
+		Entry("approle", VaultAuthAppRole),
+		Entry("kubernetes", VaultAuthKubernetes),
+	)
+
+	DescribeTable("should return error when authentication fails",
+		func(modify func(vc *VaultConfig), message string) {
+			vc := newConfig(VaultAuthAppRole)
+			modify(vc)
+
+			vault, err := NewVault(vc)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+			Expect(vault).To(BeNil())
+		},
+		Entry("invalid secret ID", func(vc *VaultConfig) { vc.SecretID = "wrong" }, "invalid role or secret ID"),
+		Entry("invalid token", func(vc *VaultConfig) { vc.AuthMethod, vc.Token = VaultAuthToken, "wrong" }, "permission denied"),
+		Entry("empty token", func(vc *VaultConfig) { vc.AuthMethod, vc.Token = VaultAuthToken, "" }, "token cannot be empty"),
+		Entry("missing token file", func(vc *VaultConfig) {
+			vc.AuthMethod, vc.TokenFile = VaultAuthTokenFile, filepath.Join(tempDir, "missing")
+		}, "no such file"),
+		Entry("unknown namespace", func(vc *VaultConfig) { vc.Namespace = "team-b" }, "namespace not found"),
+		Entry("unknown method", func(vc *VaultConfig) { vc.AuthMethod = "ldap" }, `unknown vault auth method "ldap"`),
+		Entry("invalid address", func(vc *VaultConfig) { vc.Address = "://vault" }, "vault"),
+	)
+
+	Describe("RenewToken", func() {
+		renew := func(vault IVaultConfig) {
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- vault.RenewToken(ctx) }()
+			DeferCleanup(func() {
+				cancel()
+				Eventually(done).Should(Receive(BeNil()))
+			})
+		}
+
+		It("should renew renewable token before it expires", func() {
+			emulator.ttl, emulator.renewTTL = 1, 1
+			vault, err := NewVault(newConfig(VaultAuthAppRole))
+			Expect(err).ToNot(HaveOccurred())
+			renew(vault)
+
+			Eventually(func() int { _, renewals := emulator.counts(); return renewals }).
+				WithTimeout(3 * time.Second).Should(BeNumerically(">=", 2))
+			logins, _ := emulator.counts()
+			Expect(logins).To(Equal(1))
+			Expect(vault.Read(context.Background(), vaultSecretPath)).To(HaveKey("APP_NAME"))
+		})
+
+		It("should log in again when token is not renewable", func() {
+			emulator.ttl, emulator.renewable = 1, false
+			vault, err := NewVault(newConfig(VaultAuthKubernetes))
+			Expect(err).ToNot(HaveOccurred())
+			renew(vault)
+
+			Eventually(func() int { logins, _ := emulator.counts(); return logins }).
+				WithTimeout(3 * time.Second).Should(BeNumerically(">=", 3))
+			_, renewals := emulator.counts()
+			Expect(renewals).To(BeZero())
+			Expect(vault.Read(context.Background(), vaultSecretPath)).To(HaveKey("APP_NAME"))
+		})
+
+		It("should log in again when renewal is capped by max TTL", func() {
+			emulator.ttl, emulator.renewTTL = 2, 1
+			vault, err := NewVault(newConfig(VaultAuthAppRole))
+			Expect(err).ToNot(HaveOccurred())
+			renew(vault)
+
+			Eventually(func() int { logins, _ := emulator.counts(); return logins }).
+				WithTimeout(4 * time.Second).Should(Equal(2))
+			_, renewals := emulator.counts()
+			Expect(renewals).To(Equal(1))
+		})
+
+		It("should warn once about static token which is not renewable", func() {
+			emulator.tokens["static-token"] = time.Now().Add(2 * time.Second)
+			emulator.renewable = false
+			vault, err := NewVault(newConfig(VaultAuthToken))
+			Expect(err).ToNot(HaveOccurred())
+
+			logs := new(logBuffer)
+			defaultLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+			DeferCleanup(slog.SetDefault, defaultLogger)
+			renew(vault)
+
+			warnings := func() int { return strings.Count(logs.String(), "[VAULT]") }
+			Eventually(warnings).Should(Equal(1))
+			Expect(logs.String()).To(ContainSubstring("replace it before it expires"))
+			Consistently(warnings, time.Second).Should(Equal(1))
+			Expect(logs.String()).ToNot(ContainSubstring("retrying"))
+			_, renewals := emulator.counts()
+			Expect(renewals).To(BeZero())
+		})
+
+		It("should not renew token which never expires", func() {
+			vault, err := NewVault(newConfig(VaultAuthToken))
+			Expect(err).ToNot(HaveOccurred())
+			renew(vault)
+
+			Consistently(func() int { _, renewals := emulator.counts(); return renewals }, 200*time.Millisecond).Should(BeZero())
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package configz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Name of the sources, they are merged in this order so the later one takes precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceDotenv  = "dotenv"
+	SourceEnv     = "env"
+	SourceSSM     = "ssm"
+	SourceVault   = "vault"
+	SourceFlags   = "flags"
+)
+
+// sourceOrder is the precedence of the sources, struct defaults come first
+var sourceOrder = []string{SourceDefault, SourceFile, SourceDotenv, SourceEnv, SourceSSM, SourceVault, SourceFlags}
+
+// ISource returns configuration values keyed by the mapstructure tag of the config struct
+type ISource interface {
+	Name() string
+	Load(ctx context.Context) (map[string]any, error)
+}
+
+// Loader merges sources into a single config struct and records which source supplied
+// each key
+type Loader struct {
+	prefix  string
+	sources []ISource
+
+	mu        sync.RWMutex
+	effective EffectiveConfig
+}
+
+// NewLoader returns loader of sources, later source overrides keys of the earlier ones.
+// Keys starting with prefix and underscore (e.g. USER_DATABASE_NAME) are stripped from
+// the prefix and take precedence over the unprefixed key of the same source.
+func NewLoader(prefix string, sources ...ISource) *Loader {
+	return &Loader{
+		prefix:  prefix,
+		sources: sources,
+	}
+}
+
+// Sources returns sources of the loader in precedence order
+func (l *Loader) Sources() []ISource {
+	return l.sources
+}
+
+// Load decodes merged sources into out, which must be a pointer to struct, and validates
+// it by `validate` tags. Field which is not supplied by any source keeps its value, or is
+// set from `default` tag when it is zero.
+func (l *Loader) Load(ctx context.Context, out any) error {
+	provenance, err := l.load(ctx, out)
+	if err != nil {
+		return err
+	}
+
+	l.setEffective(newEffectiveConfig(reflect.ValueOf(out).Elem(), provenance))
+	slog.InfoContext(ctx, "[CONFIG] configuration loaded", "sources", l.sourceNames())
+	return nil
+}
+
+// load decodes and validates sources into out and returns source of every supplied key
+func (l *Loader) load(ctx context.Context, out any) (map[string]string, error) {
+	val := reflect.ValueOf(out)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("output parameter must be a pointer to struct")
+	}
+
+	values := defaultsOf(val.Elem())
+	provenance := make(map[string]string)
+	for _, source := range l.sources {
+		sourceValues, err := source.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load configuration from %s: %w", source.Name(), err)
+		}
+
+		for key, value := range normalizeKeys(l.prefix, sourceValues) {
+			values[key] = value
+			provenance[key] = source.Name()
+		}
+	}
+
+	if err := decode("", values, out); err != nil {
+		return nil, err
+	}
+	if err := validateStruct(out); err != nil {
+		return nil, err
+	}
+	return provenance, nil
+}
+
+func (l *Loader) setEffective(effective EffectiveConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.effective = effective
+}
+
+// EffectiveConfig returns effective configuration of the last Load
+func (l *Loader) EffectiveConfig() EffectiveConfig {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.effective
+}
+
+func (l *Loader) sourceNames() []string {
+	names := make([]string, 0, len(l.sources))
+	for _, source := range l.sources {
+		names = append(names, source.Name())
+	}
+	return names
+}
+
+// normalizeKeys upper-cases keys and strips the prefix, prefixed key is applied last so
+// it wins regardless of the map iteration order
+func normalizeKeys(prefix string, values map[string]any) map[string]any {
+	prefix = strings.ToUpper(prefix)
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	normalized := make(map[string]any, len(values))
+	prefixed := make(map[string]any)
+	for key, value := range values {
+		key = strings.ToUpper(key)
+		if prefix != "" && strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			prefixed[strings.TrimPrefix(key, prefix)] = value
+			continue
+		}
+		normalized[key] = value
+	}
+	for key, value := range prefixed {
+		normalized[key] = value
+	}
+	return normalized
+}
+
+// defaultsOf returns `default` tag of zero fields, they are the first layer of the sources
+func defaultsOf(val reflect.Value) map[string]any {
+	values := make(map[string]any)
+	for _, f := range fieldsOf(val.Type(), nil) {
+		if f.hasDefault && val.FieldByIndex(f.index).IsZero() {
+			values[f.key] = f.defaultValue
+		}
+	}
+	return values
+}
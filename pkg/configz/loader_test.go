@@ -0,0 +1,234 @@
+package configz_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	awsssm "github.com/PaddleHQ/go-aws-ssm"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/wahyurudiyan/go-boilerplate/config"
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/configz"
+)
+
+type loaderConfig struct {
+	AppName  string `mapstructure:"APP_NAME"`
+	Port     int    `mapstructure:"PORT"`
+	Debug    bool   `mapstructure:"DEBUG"`
+	Region   string `mapstructure:"REGION"`
+	Timeout  string `mapstructure:"TIMEOUT"`
+	Password string `mapstructure:"PASSWORD"`
+	Database struct {
+		Host string `mapstructure:"database_host"`
+	} `mapstructure:",squash"`
+}
+
+type validatedConfig struct {
+	Driver   string        `mapstructure:"DRIVER" default:"postgres" validate:"oneof=postgres mysql"`
+	Port     string        `mapstructure:"PORT" default:"8080" validate:"port"`
+	Interval time.Duration `mapstructure:"INTERVAL" default:"3s" validate:"min=1s"`
+	Enabled  bool          `mapstructure:"ENABLED" default:"true"`
+	Addrs    []string      `mapstructure:"ADDRS" default:"localhost:6379" validate:"dive,hostname_port"`
+	Endpoint string        `mapstructure:"ENDPOINT" validate:"omitempty,url"`
+	Name     string        `mapstructure:"NAME" validate:"required"`
+	Password string        `mapstructure:"PASSWORD" validate:"max=4"`
+}
+
+type fakeParameterStore struct {
+	params map[string]string
+	err    error
+}
+
+func (f fakeParameterStore) GetAllParametersByPath(path string, decrypt bool) (*awsssm.Parameters, error) {
+	parameters := make(map[string]*awsssm.Parameter, len(f.params))
+	for name, value := range f.params {
+		parameters[path+name] = &awsssm.Parameter{Value: &value}
+	}
+	return awsssm.NewParameters(path, parameters), f.err
+}
+
+var _ = Describe("Loader", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+	})
+
+	writeFile := func(name, content string) string {
+		filename := filepath.Join(tempDir, name)
+		Expect(os.WriteFile(filename, []byte(content), 0644)).To(Succeed())
+		return filename
+	}
+
+	It("should merge sources by precedence and record their provenance", func() {
+		file := writeFile("config.yaml", "APP_NAME: from-file\nPORT: 1000\nREGION: from-file\n")
+		dotenv := writeFile(".env", "PORT=2000\nDEBUG=true\nUSER_DATABASE_HOST=from-dotenv\n")
+		GinkgoT().Setenv("TIMEOUT", "from-env")
+		GinkgoT().Setenv("PORT", "3000")
+		ssm := fakeParameterStore{params: map[string]string{"PORT": "4000", "PASSWORD": "Supersecret!"}}
+
+		loader := NewLoader("user",
+			NewFileSource(file),
+			NewDotenvSource(dotenv),
+			NewEnvSource(),
+			NewSSMSource(ssm, "/go-boilerplate/"),
+			NewFlagSource([]string{"serve", "--port=5000", "--region", "from-flags"}),
+		)
+
+		cfg := loaderConfig{AppName: "from-default"}
+		Expect(loader.Load(context.Background(), &cfg)).To(Succeed())
+		Expect(cfg.AppName).To(Equal("from-file"))
+		Expect(cfg.Port).To(Equal(5000))
+		Expect(cfg.Debug).To(BeTrue())
+		Expect(cfg.Region).To(Equal("from-flags"))
+		Expect(cfg.Timeout).To(Equal("from-env"))
+		Expect(cfg.Password).To(Equal("Supersecret!"))
+		Expect(cfg.Database.Host).To(Equal("from-dotenv"))
+
+		effective := loader.EffectiveConfig()
+		Expect(effective.Source("APP_NAME")).To(Equal(SourceFile))
+		Expect(effective.Source("DEBUG")).To(Equal(SourceDotenv))
+		Expect(effective.Source("database_host")).To(Equal(SourceDotenv))
+		Expect(effective.Source("TIMEOUT")).To(Equal(SourceEnv))
+		Expect(effective.Source("PASSWORD")).To(Equal(SourceSSM))
+		Expect(effective.Source("PORT")).To(Equal(SourceFlags))
+	})
+
+	It("should keep struct defaults of keys without source", func() {
+		loader := NewLoader("", NewFlagSource([]string{"--port=8080"}))
+
+		cfg := loaderConfig{AppName: "service-user"}
+		Expect(loader.Load(context.Background(), &cfg)).To(Succeed())
+		Expect(cfg.AppName).To(Equal("service-user"))
+		Expect(loader.EffectiveConfig().Source("APP_NAME")).To(Equal(SourceDefault))
+	})
+
+	It("should prefer prefixed key within a source", func() {
+		dotenv := writeFile(".env", "USER_APP_NAME=prefixed\nAPP_NAME=unprefixed\n")
+
+		var cfg loaderConfig
+		Expect(NewLoader("user", NewDotenvSource(dotenv)).Load(context.Background(), &cfg)).To(Succeed())
+		Expect(cfg.AppName).To(Equal("prefixed"))
+	})
+
+	It("should redact secrets from the effective config", func() {
+		loader := NewLoader("", NewFlagSource([]string{"--password=Supersecret!", "--app-name=service-user"}))
+
+		var cfg loaderConfig
+		Expect(loader.Load(context.Background(), &cfg)).To(Succeed())
+
+		var out bytes.Buffer
+		_, err := loader.EffectiveConfig().WriteTo(&out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.String()).To(MatchRegexp(`APP_NAME\s+service-user\s+flags`))
+		Expect(out.String()).To(MatchRegexp(`PASSWORD\s+\[REDACTED\]\s+flags`))
+		Expect(out.String()).ToNot(ContainSubstring("Supersecret!"))
+	})
+
+	It("should return error of the failing source", func() {
+		ssm := fakeParameterStore{err: errors.New("access denied")}
+
+		var cfg loaderConfig
+		err := NewLoader("", NewEnvSource(), NewSSMSource(ssm, "/go-boilerplate/")).Load(context.Background(), &cfg)
+		Expect(err).To(MatchError(ContainSubstring("ssm: access denied")))
+	})
+
+	It("should reject output which is not a pointer to struct", func() {
+		var cfg loaderConfig
+		Expect(NewLoader("").Load(context.Background(), cfg)).ToNot(Succeed())
+		Expect(NewLoader("").Load(context.Background(), nil)).ToNot(Succeed())
+	})
+
+	Describe("defaults and validation", func() {
+		It("should apply default of zero field without source", func() {
+			loader := NewLoader("", NewFlagSource([]string{"--name=service-user", "--enabled=false"}))
+
+			cfg := validatedConfig{Port: "9090"}
+			Expect(loader.Load(context.Background(), &cfg)).To(Succeed())
+			Expect(cfg).To(Equal(validatedConfig{
+				Driver:   "postgres",
+				Port:     "9090",
+				Interval: 3 * time.Second,
+				Enabled:  false,
+				Addrs:    []string{"localhost:6379"},
+				Name:     "service-user",
+			}))
+			Expect(loader.EffectiveConfig().Source("INTERVAL")).To(Equal(SourceDefault))
+			Expect(loader.EffectiveConfig().Source("ENABLED")).To(Equal(SourceFlags))
+		})
+
+		It("should report every violation together", func() {
+			loader := NewLoader("", NewFlagSource([]string{
+				"--driver=sqlite", "--port=70000", "--interval=1ns", "--addrs=localhost",
+				"--endpoint=not a url", "--password=Supersecret!",
+			}))
+
+			var cfg validatedConfig
+			err := loader.Load(context.Background(), &cfg)
+			Expect(errors.Is(err, ErrInvalidConfig)).To(BeTrue())
+
+			var validationErr *ValidationError
+			Expect(errors.As(err, &validationErr)).To(BeTrue())
+
+			rules := make(map[string]string)
+			for _, violation := range validationErr.Violations {
+				rules[violation.Field] = violation.Rule
+			}
+			Expect(rules).To(Equal(map[string]string{
+				"DRIVER":   "oneof",
+				"PORT":     "port",
+				"INTERVAL": "min",
+				"ADDRS[0]": "hostname_port",
+				"ENDPOINT": "url",
+				"NAME":     "required",
+				"PASSWORD": "max",
+			}))
+			Expect(err.Error()).To(ContainSubstring(`DRIVER must be one of: postgres, mysql, got "sqlite"`))
+			Expect(err.Error()).To(ContainSubstring("INTERVAL must be at least 1s"))
+			Expect(err.Error()).ToNot(ContainSubstring("Supersecret!"))
+		})
+
+		It("should load and validate the example service config", func() {
+			var cfg config.ServiceConfig
+			Expect(NewLoader("user", NewDotenvSource("../../.env.example")).Load(context.Background(), &cfg)).To(Succeed())
+			Expect(cfg.GrpcPort).To(Equal("9090"))
+			Expect(cfg.Database.DatabaseDriver).To(Equal("postgres"))
+			Expect(cfg.Redis.RedisClusterAddrs).To(HaveLen(3))
+		})
+	})
+
+	Describe("NewLoaderFromEnv", func() {
+		It("should order sources by precedence", func() {
+			GinkgoT().Setenv(EnvConfigSources, "flags, env ,dotenv")
+
+			loader, err := NewLoaderFromEnv(nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			var names []string
+			for _, source := range loader.Sources() {
+				names = append(names, source.Name())
+			}
+			Expect(names).To(Equal([]string{SourceDotenv, SourceEnv, SourceFlags}))
+		})
+
+		DescribeTable("should reject invalid bootstrap",
+			func(sources string) {
+				GinkgoT().Setenv(EnvConfigSources, sources)
+				GinkgoT().Setenv(EnvVaultAddr, "")
+				GinkgoT().Setenv(EnvConfigSSMPath, "")
+
+				_, err := NewLoaderFromEnv(nil)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("unknown source", "env,consul"),
+			Entry("default is not a source", "default"),
+			Entry("vault without address", "vault"),
+			Entry("ssm without path", "ssm"),
+		)
+	})
+})
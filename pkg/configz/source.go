@@ -0,0 +1,213 @@
+package configz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	awsssm "github.com/PaddleHQ/go-aws-ssm"
+	"github.com/spf13/viper"
+)
+
+// IParameterStore reads parameters of AWS SSM Parameter Store, it is implemented by
+// awsssm.ParameterStore
+type IParameterStore interface {
+	GetAllParametersByPath(path string, decrypt bool) (*awsssm.Parameters, error)
+}
+
+type sourceFunc struct {
+	name string
+	load func(ctx context.Context) (map[string]any, error)
+}
+
+func (s sourceFunc) Name() string {
+	return s.name
+}
+
+func (s sourceFunc) Load(ctx context.Context) (map[string]any, error) {
+	return s.load(ctx)
+}
+
+// NewFileSource reads config file, its format (json, yaml, toml, etc.) is taken from
+// the file extension
+func NewFileSource(filename string) ISource {
+	return &fileSource{name: SourceFile, filename: filename}
+}
+
+// NewDotenvSource reads KEY=VALUE dotenv file
+func NewDotenvSource(filename string) ISource {
+	return &fileSource{name: SourceDotenv, filename: filename, configType: "env"}
+}
+
+type fileSource struct {
+	name       string
+	filename   string
+	configType string
+}
+
+func (s *fileSource) Name() string {
+	return s.name
+}
+
+func (s *fileSource) Load(ctx context.Context) (map[string]any, error) {
+	if s.filename == "" {
+		return nil, errors.New("filename cannot be empty")
+	}
+
+	v := viper.New()
+	v.SetConfigFile(s.filename)
+	if s.configType != "" {
+		v.SetConfigType(s.configType)
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// Files returns the file watched by Watcher
+func (s *fileSource) Files() []string {
+	return []string{s.filename}
+}
+
+// NewEnvSource reads environment variables of the process
+func NewEnvSource() ISource {
+	return sourceFunc{name: SourceEnv, load: func(ctx context.Context) (map[string]any, error) {
+		values := make(map[string]any)
+		for _, env := range os.Environ() {
+			if key, value, ok := strings.Cut(env, "="); ok {
+				values[key] = value
+			}
+		}
+		return values, nil
+	}}
+}
+
+// NewFlagSource reads --key=value or --key value arguments, key is upper-cased and its
+// dashes are replaced with underscores, so --grpc-port=9090 sets GRPC_PORT. Flag without
+// value is true and other arguments are ignored.
+func NewFlagSource(args []string) ISource {
+	return sourceFunc{name: SourceFlags, load: func(ctx context.Context) (map[string]any, error) {
+		return parseFlags(args), nil
+	}}
+}
+
+func parseFlags(args []string) map[string]any {
+	values := make(map[string]any)
+	for i := 0; i < len(args); i++ {
+		name, ok := strings.CutPrefix(args[i], "--")
+		if !ok || name == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(name, "=")
+		if !hasValue {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				value = args[i+1]
+				i++
+			}
+		}
+		values[strings.ReplaceAll(name, "-", "_")] = value
+	}
+	return values
+}
+
+// NewSSMSource reads parameters under path of AWS SSM Parameter Store, the parameter
+// name relative to path is the key
+func NewSSMSource(store IParameterStore, path string) ISource {
+	return &ssmSource{store: store, path: path}
+}
+
+type ssmSource struct {
+	store IParameterStore
+	path  string
+}
+
+func (s *ssmSource) Name() string {
+	return SourceSSM
+}
+
+func (s *ssmSource) Load(ctx context.Context) (map[string]any, error) {
+	paramValues, err := s.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(paramValues))
+	for key, value := range paramValues {
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Version returns digest of the parameters, SSM has no version of a parameter path
+func (s *ssmSource) Version(ctx context.Context) (string, error) {
+	paramValues, err := s.parameters()
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(paramValues))
+	for key := range paramValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	digest := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(digest, "%s=%s\n", key, paramValues[key])
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+func (s *ssmSource) parameters() (map[string]string, error) {
+	params, err := s.store.GetAllParametersByPath(s.path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	paramValues := params.GetAllValues()
+	if len(paramValues) == 0 {
+		return nil, errors.New("no parameters found")
+	}
+	return paramValues, nil
+}
+
+// NewVaultSource reads secret at path of Vault KV v2 engine
+func NewVaultSource(vault IVaultConfig, path string) ISource {
+	return &vaultSource{vault: vault, path: path}
+}
+
+type vaultSource struct {
+	vault IVaultConfig
+	path  string
+}
+
+func (s *vaultSource) Name() string {
+	return SourceVault
+}
+
+func (s *vaultSource) Load(ctx context.Context) (map[string]any, error) {
+	return s.vault.Read(ctx, s.path)
+}
+
+// Version returns current version of the KV v2 secret
+func (s *vaultSource) Version(ctx context.Context) (string, error) {
+	return s.vault.Version(ctx, s.path)
+}
+
+// Ping reports health of Vault, so it can be registered as dependency check
+func (s *vaultSource) Ping(ctx context.Context) error {
+	return s.vault.Ping(ctx)
+}
+
+// RenewToken keeps the Vault token valid until ctx is cancelled
+func (s *vaultSource) RenewToken(ctx context.Context) error {
+	return s.vault.RenewToken(ctx)
+}
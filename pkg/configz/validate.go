@@ -0,0 +1,130 @@
+package configz
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// ErrInvalidConfig is wrapped by ValidationError
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ValidationError carries every violation of `validate` tags, so all of them are fixed
+// at once instead of one per restart
+type ValidationError struct {
+	Violations []errs.Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// validate reports field by its config key, e.g. GRPC_PORT
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return strings.ToUpper(name)
+	})
+
+	// Built-in port only accepts unsigned integer, while ports are configured as string
+	if err := v.RegisterValidation("port", isPort); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func isPort(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		port, err := strconv.ParseUint(field.String(), 10, 16)
+		return err == nil && port >= 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() >= 1 && field.Int() <= 65535
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() >= 1 && field.Uint() <= 65535
+	}
+	return false
+}
+
+// validateStruct validates out by its `validate` tags
+func validateStruct(out any) error {
+	err := validate.Struct(out)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	violations := make([]errs.Violation, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		violations = append(violations, errs.Violation{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Message: message(fieldErr),
+		})
+	}
+	return &ValidationError{Violations: violations}
+}
+
+func message(fieldErr validator.FieldError) string {
+	field, param := fieldErr.Field(), fieldErr.Param()
+	isString := fieldErr.Kind() == reflect.String
+
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "required_without_all":
+		return fmt.Sprintf("%s is required when %s are empty", field, strings.ReplaceAll(param, " ", ", "))
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s%s", field, strings.ReplaceAll(param, " ", ", "), got(fieldErr))
+	case "min", "gte":
+		if isString {
+			return fmt.Sprintf("%s must be at least %s characters", field, param)
+		}
+		return fmt.Sprintf("%s must be at least %s%s", field, param, got(fieldErr))
+	case "max", "lte":
+		if isString {
+			return fmt.Sprintf("%s must be at most %s characters", field, param)
+		}
+		return fmt.Sprintf("%s must be at most %s%s", field, param, got(fieldErr))
+	case "port":
+		return fmt.Sprintf("%s must be a port between 1 and 65535%s", field, got(fieldErr))
+	case "hostname_port":
+		return fmt.Sprintf("%s must be host:port%s", field, got(fieldErr))
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL%s", field, got(fieldErr))
+	}
+
+	return fmt.Sprintf("%s failed on %s validation", field, fieldErr.Tag())
+}
+
+// got returns the invalid value for the message, value of secret is never shown
+func got(fieldErr validator.FieldError) string {
+	if isSecretKey(fieldErr.Field()) {
+		return ""
+	}
+	return fmt.Sprintf(", got %q", fmt.Sprint(fieldErr.Value()))
+}
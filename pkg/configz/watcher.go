@@ -0,0 +1,264 @@
+package configz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/graceful"
+)
+
+const (
+	defaultWatchInterval = 30 * time.Second
+	// watchDebounce coalesces burst of file events, e.g. editor writing and renaming a file
+	watchDebounce = 100 * time.Millisecond
+)
+
+// IFileSource is a source of local files, Watcher reloads when one of them changes
+type IFileSource interface {
+	ISource
+	Files() []string
+}
+
+// IVersionedSource is a remote source, Watcher polls its version and reloads when the
+// version changes
+type IVersionedSource interface {
+	ISource
+	Version(ctx context.Context) (string, error)
+}
+
+type WatchConfig struct {
+	// Interval of polling versioned sources (e.g. Vault, SSM), default is 30 seconds
+	Interval time.Duration
+}
+
+type subscription[T any] struct {
+	id   uint64
+	keys []string
+	fn   func(prev, next *T)
+}
+
+// Watcher keeps the latest valid snapshot of config struct T loaded by the loader. Only
+// keys tagged `reload:"true"` are reloaded, change of the other keys (e.g. ports, database
+// driver) is ignored with a warning until the service is restarted.
+type Watcher[T any] struct {
+	loader   *Loader
+	interval time.Duration
+	fields   []field
+
+	current  atomic.Pointer[T]
+	reloadMu sync.Mutex
+
+	mu            sync.Mutex
+	nextID        uint64
+	subscriptions []subscription[T]
+}
+
+// NewWatcher returns watcher of config loaded by the loader, initial is the current
+// snapshot and must not be modified afterwards
+func NewWatcher[T any](loader *Loader, initial *T, cfg WatchConfig) *Watcher[T] {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultWatchInterval
+	}
+
+	w := &Watcher[T]{
+		loader:   loader,
+		interval: cfg.Interval,
+		fields:   fieldsOf(reflect.TypeOf(initial).Elem(), nil),
+	}
+	w.current.Store(initial)
+	return w
+}
+
+// Current returns the latest snapshot, it is shared and must not be modified
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Subscribe calls fn after reload changed one of the keys, or any key when keys is empty.
+// fn is called on the reloading goroutine, one reload at a time.
+func (w *Watcher[T]) Subscribe(fn func(prev, next *T), keys ...string) (unsubscribe func()) {
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.ToUpper(key)
+		if !slices.ContainsFunc(w.fields, func(f field) bool { return f.key == key }) {
+			slog.Warn("[CONFIG] subscribed to unknown key", "key", key)
+		}
+		normalized = append(normalized, key)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.nextID++
+	id := w.nextID
+	w.subscriptions = append(w.subscriptions, subscription[T]{id: id, keys: normalized, fn: fn})
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.subscriptions = slices.DeleteFunc(w.subscriptions, func(s subscription[T]) bool { return s.id == id })
+	}
+}
+
+// Reload loads and validates every source, then swaps the snapshot when a reloadable
+// key has changed. Invalid configuration is returned and the current snapshot is kept.
+func (w *Watcher[T]) Reload(ctx context.Context) error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	next := new(T)
+	provenance, err := w.loader.load(ctx, next)
+	if err != nil {
+		return fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	prev := w.current.Load()
+	prevVal, nextVal := reflect.ValueOf(prev).Elem(), reflect.ValueOf(next).Elem()
+
+	var changed []string
+	for _, f := range w.fields {
+		prevField, nextField := prevVal.FieldByIndex(f.index), nextVal.FieldByIndex(f.index)
+		if reflect.DeepEqual(prevField.Interface(), nextField.Interface()) {
+			continue
+		}
+		if !f.reloadable {
+			slog.WarnContext(ctx, "[CONFIG] change of non reloadable key is ignored until restart", "key", f.key)
+			nextField.Set(prevField)
+			continue
+		}
+		changed = append(changed, f.key)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	w.current.Store(next)
+	w.loader.setEffective(newEffectiveConfig(nextVal, provenance))
+	slog.InfoContext(ctx, "[CONFIG] configuration reloaded", "keys", changed)
+
+	w.mu.Lock()
+	subscriptions := slices.Clone(w.subscriptions)
+	w.mu.Unlock()
+
+	for _, s := range subscriptions {
+		if len(s.keys) == 0 || slices.ContainsFunc(s.keys, func(key string) bool { return slices.Contains(changed, key) }) {
+			s.fn(prev, next)
+		}
+	}
+	return nil
+}
+
+// Run reloads when file of file sources changes or version of versioned sources changes,
+// it blocks until ctx is cancelled
+func (w *Watcher[T]) Run(ctx context.Context) error {
+	fileWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fileWatcher.Close()
+
+	files := make(map[string]bool)
+	var versioned []IVersionedSource
+	for _, source := range w.loader.Sources() {
+		switch source := source.(type) {
+		case IFileSource:
+			for _, file := range source.Files() {
+				file, err := filepath.Abs(file)
+				if err != nil {
+					return err
+				}
+				// Directory is watched, so file replaced by rename or by Kubernetes
+				// ConfigMap symlink swap is still detected
+				if err := fileWatcher.Add(filepath.Dir(file)); err != nil {
+					return fmt.Errorf("failed to watch %s: %w", file, err)
+				}
+				files[file] = true
+			}
+		case IVersionedSource:
+			versioned = append(versioned, source)
+		}
+	}
+
+	var poll <-chan time.Time
+	versions := w.versions(ctx, versioned, nil)
+	if len(versioned) > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-fileWatcher.Events:
+			if !ok {
+				return nil
+			}
+			name, _ := filepath.Abs(event.Name)
+			if files[name] || strings.HasPrefix(filepath.Base(name), "..") {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-fileWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.WarnContext(ctx, "[CONFIG] file watcher error", "error", err)
+		case <-debounce.C:
+			w.reload(ctx)
+		case <-poll:
+			current := w.versions(ctx, versioned, versions)
+			if maps.Equal(current, versions) {
+				continue
+			}
+			// Version is kept until reload succeeds, so invalid change is retried
+			if w.reload(ctx) {
+				versions = current
+			}
+		}
+	}
+}
+
+// versions returns version of every source, previous version is kept when it cannot be read
+func (w *Watcher[T]) versions(ctx context.Context, sources []IVersionedSource, previous map[string]string) map[string]string {
+	versions := make(map[string]string, len(sources))
+	for _, source := range sources {
+		version, err := source.Version(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "[CONFIG] failed to read version of configuration source", "source", source.Name(), "error", err)
+			version = previous[source.Name()]
+		}
+		versions[source.Name()] = version
+	}
+	return versions
+}
+
+func (w *Watcher[T]) reload(ctx context.Context) bool {
+	if err := w.Reload(ctx); err != nil {
+		slog.WarnContext(ctx, "[CONFIG] keeping current configuration", "error", err)
+		return false
+	}
+	return true
+}
+
+// Component returns graceful component which watches the sources and reloads on SIGHUP
+func (w *Watcher[T]) Component(name string) graceful.Component {
+	return graceful.Component{
+		Name:   name,
+		Run:    w.Run,
+		Reload: w.Reload,
+	}
+}
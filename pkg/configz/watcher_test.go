@@ -0,0 +1,174 @@
+package configz_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/configz"
+)
+
+type reloadConfig struct {
+	Port     string `mapstructure:"PORT" validate:"port"`
+	LogLevel string `mapstructure:"LOG_LEVEL" validate:"oneof=debug info" reload:"true"`
+	Limits   struct {
+		Policy string `mapstructure:"POLICY"`
+	} `mapstructure:",squash" reload:"true"`
+}
+
+// versionedSource is a remote source whose version is changed by the test
+type versionedSource struct {
+	mu      sync.Mutex
+	values  map[string]any
+	version string
+}
+
+func (s *versionedSource) Name() string {
+	return SourceVault
+}
+
+func (s *versionedSource) Load(ctx context.Context) (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values, nil
+}
+
+func (s *versionedSource) Version(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version, nil
+}
+
+func (s *versionedSource) set(version string, values map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version, s.values = version, values
+}
+
+var _ = Describe("Watcher", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		dotenv string
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		dotenv = filepath.Join(GinkgoT().TempDir(), ".env")
+		writeDotenv(dotenv, "PORT=8080\nLOG_LEVEL=debug\nPOLICY=*=100/1s\n")
+	})
+
+	newWatcher := func(sources ...ISource) *Watcher[reloadConfig] {
+		loader := NewLoader("", sources...)
+		cfg := new(reloadConfig)
+		Expect(loader.Load(ctx, cfg)).To(Succeed())
+		return NewWatcher(loader, cfg, WatchConfig{Interval: 10 * time.Millisecond})
+	}
+
+	run := func(watcher *Watcher[reloadConfig]) {
+		done := make(chan error, 1)
+		go func() { done <- watcher.Run(ctx) }()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	}
+
+	It("should reload when the file changes and notify subscribers of changed keys", func() {
+		watcher := newWatcher(NewDotenvSource(dotenv))
+		initial := watcher.Current()
+
+		logLevels := make(chan string, 1)
+		watcher.Subscribe(func(prev, next *reloadConfig) {
+			Expect(prev).To(Equal(initial))
+			logLevels <- next.LogLevel
+		}, "log_level")
+		watcher.Subscribe(func(prev, next *reloadConfig) {
+			Fail("subscriber of unchanged key is notified")
+		}, "POLICY")
+
+		run(watcher)
+		// Let the watcher start watching the directory
+		time.Sleep(50 * time.Millisecond)
+		writeDotenv(dotenv, "PORT=8080\nLOG_LEVEL=info\nPOLICY=*=100/1s\n")
+
+		Eventually(logLevels).WithTimeout(3 * time.Second).Should(Receive(Equal("info")))
+		Expect(watcher.Current().LogLevel).To(Equal("info"))
+		Expect(initial.LogLevel).To(Equal("debug"))
+	})
+
+	It("should keep non reloadable key until restart", func() {
+		watcher := newWatcher(NewDotenvSource(dotenv))
+		writeDotenv(dotenv, "PORT=9090\nLOG_LEVEL=debug\nPOLICY=*=5/1m\n")
+
+		Expect(watcher.Reload(ctx)).To(Succeed())
+		Expect(watcher.Current().Port).To(Equal("8080"))
+		Expect(watcher.Current().Limits.Policy).To(Equal("*=5/1m"))
+	})
+
+	It("should keep the current snapshot when the configuration is invalid", func() {
+		watcher := newWatcher(NewDotenvSource(dotenv))
+		initial := watcher.Current()
+		watcher.Subscribe(func(prev, next *reloadConfig) {
+			Fail("subscriber is notified of invalid configuration")
+		})
+		writeDotenv(dotenv, "PORT=8080\nLOG_LEVEL=trace\n")
+
+		err := watcher.Reload(ctx)
+		Expect(errors.Is(err, ErrInvalidConfig)).To(BeTrue())
+		Expect(watcher.Current()).To(BeIdenticalTo(initial))
+	})
+
+	It("should stop notifying unsubscribed callback", func() {
+		watcher := newWatcher(NewDotenvSource(dotenv))
+		unsubscribe := watcher.Subscribe(func(prev, next *reloadConfig) {
+			Fail("unsubscribed callback is notified")
+		})
+		unsubscribe()
+		writeDotenv(dotenv, "PORT=8080\nLOG_LEVEL=info\n")
+
+		Expect(watcher.Reload(ctx)).To(Succeed())
+		Expect(watcher.Current().LogLevel).To(Equal("info"))
+	})
+
+	It("should poll versioned source and reload when its version changes", func() {
+		source := &versionedSource{version: "1", values: map[string]any{"PORT": "8080", "LOG_LEVEL": "debug"}}
+		watcher := newWatcher(source)
+
+		var mu sync.Mutex
+		reloads := 0
+		watcher.Subscribe(func(prev, next *reloadConfig) {
+			mu.Lock()
+			defer mu.Unlock()
+			reloads++
+		})
+		run(watcher)
+		// Let the watcher read the baseline version
+		time.Sleep(50 * time.Millisecond)
+
+		source.set("2", map[string]any{"PORT": "8080", "LOG_LEVEL": "info"})
+		Eventually(func() string { return watcher.Current().LogLevel }).Should(Equal("info"))
+
+		// Same version is not reloaded even though its values changed
+		source.set("2", map[string]any{"PORT": "8080", "LOG_LEVEL": "debug"})
+		Consistently(func() string { return watcher.Current().LogLevel }, 100*time.Millisecond).Should(Equal("info"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(reloads).To(Equal(1))
+	})
+})
+
+func writeDotenv(filename, content string) {
+	// Write and rename like editors do, so the file is never read half written
+	temp := filename + ".tmp"
+	Expect(os.WriteFile(temp, []byte(content), 0644)).To(Succeed())
+	Expect(os.Rename(temp, filename)).To(Succeed())
+}
@@ -0,0 +1,172 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Kind classifies domain error, every kind is translated into one HTTP status and gRPC code
+type Kind uint8
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindTooManyRequests
+	KindUnprocessable
+)
+
+// Stable error codes returned to client in RESTBodyError.Code and gRPC error detail,
+// never change the value of released code
+const (
+	CodeValidation      = 1022
+	CodeUnprocessable   = 1023
+	CodeTooManyRequests = 1029
+	CodeInternal        = 1034
+	CodeUnauthorized    = 1041
+	CodeForbidden       = 1043
+	CodeNotFound        = 1044
+	CodeConflict        = 1049
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindValidation:
+		return "validation"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindTooManyRequests:
+		return "too_many_requests"
+	case KindUnprocessable:
+		return "unprocessable"
+	default:
+		return "internal"
+	}
+}
+
+// Code returns the stable error code of the kind
+func (k Kind) Code() int {
+	switch k {
+	case KindValidation:
+		return CodeValidation
+	case KindUnauthorized:
+		return CodeUnauthorized
+	case KindForbidden:
+		return CodeForbidden
+	case KindNotFound:
+		return CodeNotFound
+	case KindConflict:
+		return CodeConflict
+	case KindTooManyRequests:
+		return CodeTooManyRequests
+	case KindUnprocessable:
+		return CodeUnprocessable
+	default:
+		return CodeInternal
+	}
+}
+
+// Violation describes a single invalid input field, Rule is the failed validation rule
+type Violation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Error is typed domain error. Message is safe to be shown to client, while the
+// wrapped Err keeps the underlying cause (e.g. driver error) for logging.
+type Error struct {
+	Kind       Kind
+	Code       int
+	Message    string
+	Violations []Violation
+	Err        error
+}
+
+// New returns domain error of the given kind with the default code of the kind
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Code: kind.Code(), Message: message}
+}
+
+func Validation(message string) *Error   { return New(KindValidation, message) }
+func Unauthorized(message string) *Error { return New(KindUnauthorized, message) }
+func Forbidden(message string) *Error    { return New(KindForbidden, message) }
+func NotFound(message string) *Error     { return New(KindNotFound, message) }
+func Conflict(message string) *Error     { return New(KindConflict, message) }
+func Internal(message string) *Error     { return New(KindInternal, message) }
+
+func TooManyRequests(message string) *Error { return New(KindTooManyRequests, message) }
+func Unprocessable(message string) *Error   { return New(KindUnprocessable, message) }
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports the same error regardless of the wrapped cause, so error returned by
+// Wrap still matches its sentinel with errors.Is
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind && e.Code == t.Code && e.Message == t.Message
+}
+
+// Wrap returns copy of the error with cause attached
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Err = cause
+	return &wrapped
+}
+
+// WithViolations returns copy of the error with field violations attached
+func (e *Error) WithViolations(violations ...Violation) *Error {
+	withViolations := *e
+	withViolations.Violations = append(slices.Clone(e.Violations), violations...)
+	return &withViolations
+}
+
+// As returns the first domain error in the err chain
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
+
+// KindOf returns kind of the error, error outside the catalog is KindInternal
+func KindOf(err error) Kind {
+	if domainErr, ok := As(err); ok {
+		return domainErr.Kind
+	}
+	return KindInternal
+}
+
+// IsKind reports whether any error in err chain is domain error of the given kind
+func IsKind(err error, kind Kind) bool {
+	domainErr, ok := As(err)
+	return ok && domainErr.Kind == kind
+}
+
+// isContextError reports cancelled or timed out request, those are not internal failures
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
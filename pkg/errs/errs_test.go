@@ -0,0 +1,106 @@
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+var errTestNotFound = NotFound("thing not found")
+
+func TestWrapMatchesSentinel(t *testing.T) {
+	cause := errors.New("driver error")
+	err := fmt.Errorf("lookup: %w", errTestNotFound.Wrap(cause))
+
+	if !errors.Is(err, errTestNotFound) {
+		t.Errorf("Expected wrapped error to match sentinel")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected wrapped error to keep the cause")
+	}
+	if errors.Is(err, NotFound("other thing not found")) {
+		t.Errorf("Expected error with other message not to match")
+	}
+	if KindOf(err) != KindNotFound || !IsKind(err, KindNotFound) {
+		t.Errorf("Expected kind not_found, got: %s", KindOf(err))
+	}
+	if KindOf(cause) != KindInternal {
+		t.Errorf("Expected error outside catalog to be internal, got: %s", KindOf(cause))
+	}
+}
+
+func TestTranslators(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		httpStatus int
+		code       int
+		grpcCode   codes.Code
+		reason     string
+	}{
+		{"validation", Validation("bad input"), http.StatusBadRequest, CodeValidation, codes.InvalidArgument, "bad input"},
+		{"unauthorized", Unauthorized("who are you"), http.StatusUnauthorized, CodeUnauthorized, codes.Unauthenticated, "who are you"},
+		{"forbidden", Forbidden("go away"), http.StatusForbidden, CodeForbidden, codes.PermissionDenied, "go away"},
+		{"not found", fmt.Errorf("%w: id 1", errTestNotFound), http.StatusNotFound, CodeNotFound, codes.NotFound, "thing not found"},
+		{"conflict hides cause", Conflict("already exists").Wrap(errors.New("duplicate key")), http.StatusConflict, CodeConflict, codes.AlreadyExists, "already exists"},
+		{"too many requests", TooManyRequests("slow down"), http.StatusTooManyRequests, CodeTooManyRequests, codes.ResourceExhausted, "slow down"},
+		{"unprocessable", Unprocessable("cannot do that"), http.StatusUnprocessableEntity, CodeUnprocessable, codes.FailedPrecondition, "cannot do that"},
+		{"internal", Internal("broken"), http.StatusInternalServerError, CodeInternal, codes.Internal, "internal server error"},
+		{"unknown is hidden", errors.New("connection refused"), http.StatusInternalServerError, CodeInternal, codes.Internal, "internal server error"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpStatus, body := ToREST(tc.err)
+			if httpStatus != tc.httpStatus || body.Error == nil || body.Error.Code != tc.code || body.Error.Reason != tc.reason {
+				t.Errorf("Unexpected REST translation: %d %+v", httpStatus, body.Error)
+			}
+
+			st := ToGRPCStatus(tc.err)
+			if st.Code() != tc.grpcCode || st.Message() != tc.reason {
+				t.Errorf("Unexpected gRPC translation: %s %q", st.Code(), st.Message())
+			}
+		})
+	}
+}
+
+func TestTranslateViolations(t *testing.T) {
+	err := Validation("invalid request").WithViolations(Violation{Field: "email", Rule: "email", Message: "email must be a valid email address"})
+
+	_, body := ToREST(err)
+	if len(body.Error.Violations) != 1 || body.Error.Violations[0].Field != "email" {
+		t.Errorf("Expected violation on REST body, got: %+v", body.Error)
+	}
+
+	details := ToGRPCStatus(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected BadRequest detail, got: %+v", details)
+	}
+	badRequest, ok := details[0].(*errdetails.BadRequest)
+	if !ok || badRequest.GetFieldViolations()[0].GetField() != "email" {
+		t.Errorf("Unexpected gRPC detail: %+v", details[0])
+	}
+}
+
+func TestGRPCStatusPassthrough(t *testing.T) {
+	if GRPCError(nil) != nil {
+		t.Errorf("Expected nil error to stay nil")
+	}
+
+	err := status.Error(codes.Unavailable, "unavailable")
+	if status.Code(GRPCError(err)) != codes.Unavailable {
+		t.Errorf("Expected status error to be returned as is")
+	}
+
+	if status.Code(GRPCError(context.DeadlineExceeded)) != codes.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded to keep its code")
+	}
+}
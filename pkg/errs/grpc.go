@@ -0,0 +1,87 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCCode returns gRPC status code of the error kind
+func (k Kind) GRPCCode() codes.Code {
+	switch k {
+	case KindValidation:
+		return codes.InvalidArgument
+	case KindUnauthorized:
+		return codes.Unauthenticated
+	case KindForbidden:
+		return codes.PermissionDenied
+	case KindNotFound:
+		return codes.NotFound
+	case KindConflict:
+		return codes.AlreadyExists
+	case KindTooManyRequests:
+		return codes.ResourceExhausted
+	case KindUnprocessable:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+// ToGRPCStatus translates err into gRPC status, error that already carries status
+// is returned as is. Error outside the catalog is logged and hidden behind codes.Internal.
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	if domainErr, ok := As(err); ok && domainErr.Kind != KindInternal {
+		st := status.New(domainErr.Kind.GRPCCode(), domainErr.Message)
+		if len(domainErr.Violations) == 0 {
+			return st
+		}
+		return withBadRequest(st, domainErr.Violations)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+
+	slog.Error("Unhandled internal error", "error", err)
+	return status.New(codes.Internal, "internal server error")
+}
+
+// withBadRequest attaches field violations as errdetails.BadRequest so client can read
+// it the same way as google APIs
+func withBadRequest(st *status.Status, violations []Violation) *status.Status {
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Reason:      violation.Rule,
+			Description: violation.Message,
+		})
+	}
+
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// GRPCError is shorthand of ToGRPCStatus(err).Err()
+func GRPCError(err error) error {
+	return ToGRPCStatus(err).Err()
+}
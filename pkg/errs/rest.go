@@ -0,0 +1,51 @@
+package errs
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/common"
+)
+
+// HTTPStatus returns HTTP status code of the error kind
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ToREST translates err into HTTP status and common.RESTBody. Reason of domain error
+// is its client safe message, error outside the catalog is logged and hidden.
+//
+//	c.JSON(errs.ToREST(err))
+func ToREST(err error) (int, common.RESTBody[any]) {
+	if domainErr, ok := As(err); ok && domainErr.Kind != KindInternal {
+		body := common.RESTErrorResponse[any](domainErr.Code, domainErr.Message)
+		for _, violation := range domainErr.Violations {
+			body.Error.Violations = append(body.Error.Violations, common.RESTFieldViolation(violation))
+		}
+		return domainErr.Kind.HTTPStatus(), body
+	}
+
+	if isContextError(err) {
+		return http.StatusRequestTimeout, common.RESTErrorResponse[any](CodeInternal, "request cancelled or timed out")
+	}
+
+	slog.Error("Unhandled internal error", "error", err)
+	return http.StatusInternalServerError, common.RESTErrorResponse[any](CodeInternal, "internal server error")
+}
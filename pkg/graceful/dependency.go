@@ -0,0 +1,70 @@
+package graceful
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// sortComponents orders components topologically so every component comes after its
+// dependencies, the given order is kept between independent components.
+func sortComponents(components []Component) ([]Component, error) {
+	index := make(map[string]int, len(components))
+	for i, component := range components {
+		if _, ok := index[component.Name]; ok {
+			return nil, fmt.Errorf("%w: component %q is registered more than once", ErrInvalidDependency, component.Name)
+		}
+		index[component.Name] = i
+	}
+
+	for _, component := range components {
+		for _, dependency := range component.DependsOn {
+			if _, ok := index[dependency]; !ok {
+				return nil, fmt.Errorf("%w: component %q depends on unknown component %q", ErrInvalidDependency, component.Name, dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(components))
+	sorted := make([]Component, 0, len(components))
+	var path []string
+
+	// visit walks dependencies depth first, component found while still visiting is a cycle
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, components[i].Name):], components[i].Name)
+			return fmt.Errorf("%w: dependency cycle %s", ErrInvalidDependency, strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, components[i].Name)
+		for _, dependency := range components[i].DependsOn {
+			if err := visit(index[dependency]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+
+		sorted = append(sorted, components[i])
+		return nil
+	}
+
+	for i := range components {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
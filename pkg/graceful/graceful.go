@@ -2,139 +2,269 @@ package graceful
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
 
-type ShutdownCallback func(ctx context.Context) error
+var (
+	// ErrShutdownTimeout is returned for component that does not stop before the shutdown timeout
+	ErrShutdownTimeout = errors.New("shutdown timeout exceeded")
+	// ErrInvalidDependency is returned before any component is started when component
+	// name is duplicated, dependency is unknown or dependencies form a cycle
+	ErrInvalidDependency = errors.New("invalid component dependency")
+)
 
-type ExecCallback func(ctx context.Context) (ShutdownCallback, error)
+// Component is a part of the service which lifecycle is managed by Run.
+//
+// A component either starts with Start, which must return once the component is ready,
+// or with Run, which blocks serving until ctx is cancelled (e.g. server loop). Component
+// without Start and Run (e.g. telemetry exporter) is started as soon as Run reaches it.
+type Component struct {
+	Name string
 
-func Run(ctx context.Context, timeout time.Duration, ops map[string]ExecCallback) error {
-	// Create a base context for running operations
-	runCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	// DependsOn lists name of components that must be started before this component
+	// and stopped after it
+	DependsOn []string
 
-	// Start all operations and collect shutdown callbacks
-	shutdownCallbacks := make(map[string]ShutdownCallback)
-	var startupWg sync.WaitGroup
-	var startupErr error
-	var startupErrMu sync.Mutex
-
-	// Start all services concurrently
-	for name, operation := range ops {
-		startupWg.Add(1)
-		go func(name string, operation ExecCallback) {
-			defer startupWg.Done()
-
-			slog.InfoContext(ctx, "[Graceful] 🚀 starting service", "name", name)
-
-			callback, err := operation(runCtx)
-			if err != nil {
-				slog.ErrorContext(ctx, "[Graceful] ⛔ failed to start service", "name", name, "error", err)
-				startupErrMu.Lock()
-				if startupErr == nil {
-					startupErr = err
-				}
-				startupErrMu.Unlock()
-				cancel() // Cancel context to signal other operations to terminate
-				return
-			}
+	// StopTimeout bounds Stop of this component, zero means only the Run timeout applies
+	StopTimeout time.Duration
 
-			if callback != nil {
-				slog.InfoContext(ctx, "[Graceful] ✅ service started successfully", "name", name)
-			}
-		}(name, operation)
+	// Start starts the component without blocking, returned error is a startup failure
+	Start func(ctx context.Context) error
+
+	// Run serves the component until ctx is cancelled, returning error at any time before
+	// shutdown is a failure and shuts the whole service down
+	Run func(ctx context.Context) error
+
+	// Stop is called once on shutdown for every started component, the ctx carries
+	// the shutdown deadline. It is optional for Run component because ctx given to Run
+	// is cancelled right after Stop.
+	Stop func(ctx context.Context) error
+
+	// Reload is called on SIGHUP for every started component in dependency order, its
+	// error is logged and does not stop the service
+	Reload func(ctx context.Context) error
+}
+
+// runningComponent keeps state of started component
+type runningComponent struct {
+	Component
+	cancel   context.CancelFunc
+	done     chan struct{} // closed when Run returns, nil for Start component
+	stopping atomic.Bool
+}
+
+// Run starts components in dependency order and blocks until ctx is cancelled, shutdown
+// signal is received or one of the components fails. Then every started component is
+// stopped in reverse order within timeout. Components without dependency between them
+// keep the given order. Every startup, runtime and shutdown error is returned joined together.
+//
+// SIGHUP received after startup reloads the started components instead of shutting down.
+func Run(ctx context.Context, timeout time.Duration, components ...Component) error {
+	components, err := sortComponents(components)
+	if err != nil {
+		slog.ErrorContext(ctx, "[Graceful] ⛔ invalid component dependency", "error", err)
+		return err
 	}
 
-	go func() {
-		// Wait for all services to start or fail
-		startupWg.Wait()
-	}()
+	// Define graceful shutdown
+	shutdownSignals := []os.Signal{
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT,
+	}
 
-	// Check if any service failed to start
-	startupErrMu.Lock()
-	hasStartupError := startupErr != nil
-	startupErrMu.Unlock()
+	sigCtx, stop := signal.NotifyContext(ctx, shutdownSignals...)
+	defer stop()
 
-	if hasStartupError {
-		slog.ErrorContext(ctx, "[Graceful] ⛔ one or more services failed to start")
-		// Still proceed with shutdown for any services that did start
-	} else {
+	reloadSignal := make(chan os.Signal, 1)
+	signal.Notify(reloadSignal, syscall.SIGHUP)
+	defer signal.Stop(reloadSignal)
+
+	// failCtx is cancelled by failure of any component to initiate shutdown
+	failCtx, fail := context.WithCancelCause(sigCtx)
+	defer fail(nil)
+
+	var runErrs errorList
+	started := make([]*runningComponent, 0, len(components))
+	for _, component := range components {
+		if failCtx.Err() != nil {
+			break
+		}
+
+		slog.InfoContext(ctx, "[Graceful] 🚀 starting service", "name", component.Name)
+		running, err := start(ctx, component, func(err error) {
+			slog.ErrorContext(ctx, "[Graceful] ⛔ service failed", "name", component.Name, "error", err)
+			runErrs.add(fmt.Errorf("%s: %w", component.Name, err))
+			fail(err)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "[Graceful] ⛔ failed to start service", "name", component.Name, "error", err)
+			runErrs.add(fmt.Errorf("%s: start: %w", component.Name, err))
+			fail(err)
+			break
+		}
+
+		started = append(started, running)
+		slog.InfoContext(ctx, "[Graceful] ✅ service started successfully", "name", component.Name)
+	}
+
+	if len(started) == len(components) && failCtx.Err() == nil {
 		slog.InfoContext(ctx, "[Graceful] 🌟 all services started successfully")
+	} else if context.Cause(failCtx) != context.Canceled {
+		slog.ErrorContext(ctx, "[Graceful] ⛔ one or more services failed to start")
 	}
 
-	// Initiate shutdown process
-	slog.InfoContext(ctx, "[Graceful] 🌟 register service shutdown callback")
-	wait, err := Shutdown(runCtx, timeout, shutdownCallbacks)
-	if err != nil {
-		return err
+	// Wait for signal, parent cancellation or component failure
+	for failCtx.Err() == nil {
+		select {
+		case <-failCtx.Done():
+		case <-reloadSignal:
+			reload(failCtx, started)
+		}
 	}
+	slog.InfoContext(ctx, "[Graceful] 📡 shutdown signal received, initiating graceful shutdown")
 
-	// Wait for shutdown to complete
-	<-wait
+	// Create timeout context for shutdown operations
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
 
-	startupErrMu.Lock()
-	defer startupErrMu.Unlock()
-	return startupErr
-}
+	stopFailed := false
+	for i := len(started) - 1; i >= 0; i-- {
+		name := started[i].Name
+		slog.InfoContext(ctx, "[Graceful] 🔻 shutting down", "name", name)
+		if err := started[i].stopWithin(shutdownCtx); err != nil {
+			slog.ErrorContext(ctx, "[Graceful] ⛔ failed to shut down", "name", name, "error", err)
+			runErrs.add(fmt.Errorf("%s: stop: %w", name, err))
+			stopFailed = true
+			continue
+		}
+		slog.InfoContext(ctx, "[Graceful] 👍 shutdown successfully", "name", name)
+	}
 
-func Shutdown(ctx context.Context, timeout time.Duration, ops map[string]ShutdownCallback) (<-chan struct{}, error) {
-	wait := make(chan struct{})
+	if shutdownCtx.Err() != nil {
+		slog.WarnContext(ctx, "[Graceful] ⏱️ shutdown timeout before all services could shut down cleanly")
+	} else if !stopFailed {
+		slog.InfoContext(ctx, "[Graceful] 🛬 all services shut down successfully")
+	}
 
-	go func() {
-		defer close(wait)
+	return runErrs.join()
+}
+
+// reload calls Reload of started components in dependency order
+func reload(ctx context.Context, started []*runningComponent) {
+	slog.InfoContext(ctx, "[Graceful] 🔄 reload signal received")
+	for _, running := range started {
+		if running.Reload == nil {
+			continue
+		}
 
-		// Define graceful shutdown
-		shutdownSignals := []os.Signal{
-			syscall.SIGINT,
-			syscall.SIGTERM,
-			syscall.SIGQUIT,
+		if err := running.Reload(ctx); err != nil {
+			slog.WarnContext(ctx, "[Graceful] ⚠️ failed to reload", "name", running.Name, "error", err)
+			continue
 		}
+		slog.InfoContext(ctx, "[Graceful] 🔁 reloaded successfully", "name", running.Name)
+	}
+}
+
+// errorList collects errors from concurrently running components
+type errorList struct {
+	mu   sync.Mutex
+	errs []error
+}
 
-		sigCtx, stop := signal.NotifyContext(ctx, shutdownSignals...)
-		defer stop()
-
-		// Wait for signal
-		<-sigCtx.Done()
-		slog.InfoContext(ctx, "[Graceful] 📡 shutdown signal received, initiating graceful shutdown")
-
-		// Create timeout context for shutdown operations
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-
-		var wg sync.WaitGroup
-		for name, operation := range ops {
-			wg.Add(1)
-			go func(name string, operation ShutdownCallback) {
-				defer wg.Done()
-				slog.Info("[Graceful] 🔻 shutting down", "name", name)
-				if err := operation(shutdownCtx); err != nil {
-					slog.ErrorContext(shutdownCtx, "[Graceful] ⛔ failed to shut down", "name", name, "error", err)
-					return
-				}
-				slog.InfoContext(shutdownCtx, "[Graceful] 👍 shutdown successfully", "name", name)
-			}(name, operation)
+func (l *errorList) add(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, err)
+}
+
+func (l *errorList) join() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return errors.Join(l.errs...)
+}
+
+// start starts the component, onFailure is called when Run component fails before it is stopped
+func start(ctx context.Context, component Component, onFailure func(error)) (*runningComponent, error) {
+	// Component context is cancelled only by its own shutdown, so components are stopped in order
+	componentCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	running := &runningComponent{Component: component, cancel: cancel}
+
+	if component.Start != nil {
+		if err := component.Start(componentCtx); err != nil {
+			cancel()
+			return nil, err
 		}
+	}
 
-		// Wait for all operations to complete or timeout
-		done := make(chan struct{})
+	if component.Run != nil {
+		running.done = make(chan struct{})
 		go func() {
-			wg.Wait()
-			close(done)
+			defer close(running.done)
+			// Error caused by Stop or ctx cancellation is part of the shutdown, not a failure
+			err := component.Run(componentCtx)
+			if err == nil || running.stopping.Load() {
+				return
+			}
+			onFailure(err)
 		}()
+	}
 
+	return running, nil
+}
+
+// stopWithin applies StopTimeout of the component on top of the shutdown deadline
+func (r *runningComponent) stopWithin(ctx context.Context) error {
+	if r.StopTimeout <= 0 {
+		return r.stop(ctx)
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, r.StopTimeout)
+	defer cancel()
+	return r.stop(stopCtx)
+}
+
+// stop calls Stop and cancels the component context, it waits for Run to return
+// but never longer than ctx deadline.
+func (r *runningComponent) stop(ctx context.Context) error {
+	r.stopping.Store(true)
+
+	var err error
+	if r.Stop != nil {
+		err = waitContext(ctx, func() error { return r.Stop(ctx) })
+	}
+	r.cancel()
+
+	if r.done != nil {
 		select {
-		case <-done:
-			slog.InfoContext(ctx, "[Graceful] 🛬 all services shut down successfully")
-		case <-shutdownCtx.Done():
-			slog.WarnContext(ctx, "[Graceful] ⏱️ shutdown timeout before all services could shut down cleanly")
+		case <-r.done:
+		case <-ctx.Done():
+			err = errors.Join(err, ErrShutdownTimeout)
 		}
+	}
+
+	return err
+}
+
+// waitContext returns ErrShutdownTimeout when fn does not return before ctx is done
+func waitContext(ctx context.Context, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn()
 	}()
 
-	return wait, nil
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ErrShutdownTimeout
+	}
 }
@@ -62,10 +62,12 @@ func setupTestLogger() *testLogHandler {
 	return handler
 }
 
-// Simple implementation of ExecCallback for testing
-func createSuccessService(name string) ExecCallback {
-	return func(ctx context.Context) (ShutdownCallback, error) {
-		return func(ctx context.Context) error {
+// Simple implementation of Component for testing
+func createSuccessService(name string) Component {
+	return Component{
+		Name:  name,
+		Start: func(ctx context.Context) error { return nil },
+		Stop: func(ctx context.Context) error {
 			// Wait for a short period to simulate shutdown work
 			select {
 			case <-time.After(50 * time.Millisecond):
@@ -73,25 +75,60 @@ func createSuccessService(name string) ExecCallback {
 			case <-ctx.Done():
 				return ctx.Err()
 			}
-		}, nil
+		},
 	}
 }
 
 // Create a service that fails to start
-func createFailingService() ExecCallback {
-	return func(ctx context.Context) (ShutdownCallback, error) {
-		return nil, errors.New("service startup failed")
+func createFailingService() Component {
+	return Component{
+		Name:  "failing",
+		Start: func(ctx context.Context) error { return errors.New("service startup failed") },
 	}
 }
 
 // Create a service that takes a long time to shut down
-func createSlowShutdownService() ExecCallback {
-	return func(ctx context.Context) (ShutdownCallback, error) {
-		return func(ctx context.Context) error {
-			// Simulate a slow shutdown that may timeout
+func createSlowShutdownService() Component {
+	return Component{
+		Name:  "slow",
+		Start: func(ctx context.Context) error { return nil },
+		Stop: func(ctx context.Context) error {
+			// Simulate a slow shutdown that ignores the deadline
 			time.Sleep(30 * time.Second)
 			return nil
-		}, nil
+		},
+	}
+}
+
+// lifecycleRecorder records start and stop of components in order
+type lifecycleRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *lifecycleRecorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *lifecycleRecorder) getEvents() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.events...)
+}
+
+func (r *lifecycleRecorder) component(name string) Component {
+	return Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			r.record("start " + name)
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			r.record("stop " + name)
+			return nil
+		},
 	}
 }
 
@@ -109,15 +146,15 @@ func TestRunWithSuccessfulServices(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	services := map[string]ExecCallback{
-		"service1": createSuccessService("service1"),
-		"service2": createSuccessService("service2"),
+	services := []Component{
+		createSuccessService("service1"),
+		createSuccessService("service2"),
 	}
 
 	// Run in a goroutine so we can control shutdown
 	errCh := make(chan error)
 	go func() {
-		err := Run(ctx, 1*time.Second, services)
+		err := Run(ctx, 1*time.Second, services...)
 		errCh <- err
 	}()
 
@@ -139,6 +176,8 @@ func TestRunWithSuccessfulServices(t *testing.T) {
 		"starting service",
 		"service started successfully",
 		"all services started successfully",
+		"shutdown signal received",
+		"all services shut down successfully",
 	}
 
 	for _, phrase := range expectedPhrases {
@@ -157,32 +196,24 @@ func TestRunWithSuccessfulServices(t *testing.T) {
 
 func TestRunWithFailingService(t *testing.T) {
 	logHandler := setupTestLogger()
+	recorder := &lifecycleRecorder{}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	services := map[string]ExecCallback{
-		"service1": createSuccessService("service1"),
-		"failing":  createFailingService(),
+	services := []Component{
+		recorder.component("service1"),
+		createFailingService(),
+		recorder.component("service2"),
 	}
 
-	// Run in a goroutine so we can control shutdown
-	errCh := make(chan error)
-	go func() {
-		err := Run(ctx, 1*time.Second, services)
-		errCh <- err
-	}()
-
-	// Give services time to start and fail
-	time.Sleep(100 * time.Millisecond)
-
-	// Send shutdown signal
-	simulateSignal(syscall.SIGTERM)
+	// Startup failure must shut down without waiting for signal
+	err := Run(context.Background(), 1*time.Second, services...)
+	if err == nil || !contains(err.Error(), "service startup failed") {
+		t.Errorf("Expected startup error, got: %v", err)
+	}
 
-	// Wait for completion
-	err := <-errCh
-	if err == nil {
-		t.Error("Expected an error but got nil")
+	// Only the already started service is stopped, the rest is never started
+	want := []string{"start service1", "stop service1"}
+	if got := recorder.getEvents(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected events %v, got: %v", want, got)
 	}
 
 	// Check logs for expected messages
@@ -192,104 +223,302 @@ func TestRunWithFailingService(t *testing.T) {
 		"one or more services failed to start",
 	}
 
-	found := false
 	for _, phrase := range expectedPhrases {
+		found := false
 		for _, log := range logs {
 			if contains(log, phrase) {
 				found = true
-				return
+				break
 			}
 		}
+		if !found {
+			t.Errorf("Expected log containing '%s' not found", phrase)
+		}
+	}
+}
+
+func TestRunStopInReverseOrder(t *testing.T) {
+	setupTestLogger()
+	recorder := &lifecycleRecorder{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- Run(ctx, 1*time.Second, recorder.component("database"), recorder.component("cache"), recorder.component("server"))
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-errCh; err != nil {
+		t.Errorf("Expected no error, got: %v", err)
 	}
 
-	if !found {
-		t.Errorf("Expected log containing 'failed to start service' not found")
-		return
+	want := []string{"start database", "start cache", "start server", "stop server", "stop cache", "stop database"}
+	if got := recorder.getEvents(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected events %v, got: %v", want, got)
 	}
 }
 
-// TODO: find another way to create slow shutdown function.
-// this test PASS when the code running with debug mode.
-
-// func TestRunWithSlowShutdown(t *testing.T) {
-// 	logHandler := setupTestLogger()
-
-// 	ctx, cancel := context.WithCancel(context.Background())
-// 	defer cancel()
-
-// 	services := map[string]ExecCallback{
-// 		"slow": createSlowShutdownService(),
-// 	}
-
-// 	// Use a very short timeout to force timeout during shutdown
-// 	shortTimeout := 1 * time.Second
-
-// 	// Run in a goroutine so we can control shutdown
-// 	errCh := make(chan error)
-// 	go func() {
-// 		err := Run(ctx, shortTimeout, services)
-// 		errCh <- err
-// 	}()
-
-// 	// Give services time to start
-// 	time.Sleep(3 * time.Second)
-
-// 	// Send shutdown signal
-// 	simulateSignal(syscall.SIGTERM)
-
-// 	// Wait for completion
-// 	err := <-errCh
-// 	if err != nil {
-// 		t.Errorf("Expected no error, got: %v", err)
-// 	}
-
-// 	// Check logs for timeout warning
-// 	logs := logHandler.getLogs()
-// 	timeoutFound := false
-// 	for _, log := range logs {
-// 		fmt.Println(">>", log)
-// 		if contains(log, "timeout") {
-// 			timeoutFound = true
-// 			break
-// 		}
-// 	}
-
-// 	if !timeoutFound {
-// 		t.Error("Expected shutdown timeout warning not found in logs")
-// 	}
-// }
-
-func TestShutdownDirectly(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func TestRunBlockingComponent(t *testing.T) {
+	setupTestLogger()
 
-	var shutdownCalled bool
+	var stopped, returned bool
 	var mu sync.Mutex
-
-	callbacks := map[string]ShutdownCallback{
-		"test": func(ctx context.Context) error {
+	server := Component{
+		Name: "server",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			mu.Lock()
+			returned = true
+			mu.Unlock()
+			return ctx.Err()
+		},
+		Stop: func(ctx context.Context) error {
 			mu.Lock()
-			shutdownCalled = true
+			stopped = true
 			mu.Unlock()
 			return nil
 		},
 	}
 
-	wait, err := Shutdown(ctx, 1*time.Second, callbacks)
-	if err != nil {
-		t.Fatalf("Shutdown returned unexpected error: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := Run(ctx, 1*time.Second, server); err != nil {
+		t.Errorf("Expected cancellation during shutdown not to be an error, got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !stopped || !returned {
+		t.Errorf("Expected Stop to be called and Run to return, stopped: %v, returned: %v", stopped, returned)
+	}
+}
+
+func TestRunComponentFailure(t *testing.T) {
+	setupTestLogger()
+	recorder := &lifecycleRecorder{}
+
+	crashing := Component{
+		Name: "worker",
+		Run: func(ctx context.Context) error {
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return errors.New("worker crashed")
+			case <-ctx.Done():
+				return nil
+			}
+		},
+	}
+
+	// Runtime failure must trigger shutdown of every other component
+	done := make(chan error)
+	go func() {
+		done <- Run(context.Background(), 1*time.Second, recorder.component("database"), crashing)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !contains(err.Error(), "worker: worker crashed") {
+			t.Errorf("Expected runtime error, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected component failure to shut down the service")
+	}
+
+	if got := recorder.getEvents(); len(got) != 2 || got[1] != "stop database" {
+		t.Errorf("Expected database to be stopped, got: %v", got)
+	}
+}
+
+func TestRunAggregateErrors(t *testing.T) {
+	setupTestLogger()
+
+	errStop := errors.New("close connection failed")
+	services := []Component{
+		{
+			Name:  "database",
+			Start: func(ctx context.Context) error { return nil },
+			Stop:  func(ctx context.Context) error { return errStop },
+		},
+		createFailingService(),
+	}
+
+	err := Run(context.Background(), 1*time.Second, services...)
+	if err == nil {
+		t.Fatal("Expected an error but got nil")
+	}
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected stop error to be returned, got: %v", err)
+	}
+	if !contains(err.Error(), "failing: start: service startup failed") {
+		t.Errorf("Expected startup error to be returned, got: %v", err)
 	}
+}
+
+func TestRunWithSlowShutdown(t *testing.T) {
+	logHandler := setupTestLogger()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Use a very short timeout to force timeout during shutdown
+	shortTimeout := 100 * time.Millisecond
+
+	// Run in a goroutine so we can control shutdown
+	errCh := make(chan error)
+	go func() {
+		err := Run(ctx, shortTimeout, createSuccessService("fast"), createSlowShutdownService())
+		errCh <- err
+	}()
 
-	// Send context cancellation to trigger shutdown
+	// Give services time to start
+	time.Sleep(50 * time.Millisecond)
 	cancel()
 
-	// Wait for shutdown to complete
-	<-wait
+	// Wait for completion
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Errorf("Expected ErrShutdownTimeout, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Run to return after shutdown timeout")
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
-	if !shutdownCalled {
-		t.Error("Shutdown callback was not called")
+	// Check logs for timeout warning
+	logs := logHandler.getLogs()
+	timeoutFound := false
+	for _, log := range logs {
+		if contains(log, "timeout") {
+			timeoutFound = true
+			break
+		}
+	}
+
+	if !timeoutFound {
+		t.Error("Expected shutdown timeout warning not found in logs")
+	}
+}
+
+func TestRunDependencyOrder(t *testing.T) {
+	setupTestLogger()
+	recorder := &lifecycleRecorder{}
+
+	withDependency := func(component Component, dependsOn ...string) Component {
+		component.DependsOn = dependsOn
+		return component
+	}
+
+	// Declared in the wrong order on purpose
+	services := []Component{
+		withDependency(recorder.component("rest"), "database", "telemetry"),
+		withDependency(recorder.component("database"), "telemetry"),
+		recorder.component("telemetry"),
+		recorder.component("standalone"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- Run(ctx, 1*time.Second, services...)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-errCh; err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	want := []string{
+		"start telemetry", "start database", "start rest", "start standalone",
+		"stop standalone", "stop rest", "stop database", "stop telemetry",
+	}
+	if got := recorder.getEvents(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected events %v, got: %v", want, got)
+	}
+}
+
+func TestRunInvalidDependency(t *testing.T) {
+	setupTestLogger()
+
+	cases := []struct {
+		name       string
+		components []Component
+		wantErr    string
+	}{
+		{
+			name: "cycle",
+			components: []Component{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"c"}},
+				{Name: "c", DependsOn: []string{"a"}},
+			},
+			wantErr: "dependency cycle a -> b -> c -> a",
+		},
+		{
+			name:       "self dependency",
+			components: []Component{{Name: "a", DependsOn: []string{"a"}}},
+			wantErr:    "dependency cycle a -> a",
+		},
+		{
+			name:       "unknown dependency",
+			components: []Component{{Name: "a", DependsOn: []string{"missing"}}},
+			wantErr:    `component "a" depends on unknown component "missing"`,
+		},
+		{
+			name:       "duplicate name",
+			components: []Component{{Name: "a"}, {Name: "a"}},
+			wantErr:    `component "a" is registered more than once`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := &lifecycleRecorder{}
+			components := append(tc.components, recorder.component("other"))
+
+			err := Run(context.Background(), 1*time.Second, components...)
+			if !errors.Is(err, ErrInvalidDependency) || !contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tc.wantErr, err)
+			}
+			if events := recorder.getEvents(); len(events) != 0 {
+				t.Errorf("Expected nothing to be started, got: %v", events)
+			}
+		})
+	}
+}
+
+func TestRunPerComponentStopTimeout(t *testing.T) {
+	setupTestLogger()
+	recorder := &lifecycleRecorder{}
+
+	slow := createSlowShutdownService()
+	slow.StopTimeout = 50 * time.Millisecond
+	slow.DependsOn = []string{"database"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- Run(ctx, 10*time.Second, recorder.component("database"), slow)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrShutdownTimeout) || !contains(err.Error(), "slow: stop") {
+			t.Errorf("Expected slow component to time out, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected per-component timeout to bound the slow shutdown")
+	}
+
+	// Timeout of one component must not prevent its dependencies from stopping
+	if got := recorder.getEvents(); len(got) != 2 || got[1] != "stop database" {
+		t.Errorf("Expected database to be stopped, got: %v", got)
 	}
 }
 
@@ -299,16 +528,22 @@ func TestConcurrentStartup(t *testing.T) {
 
 	// Create a large number of services to test concurrency
 	const serviceCount = 50
-	services := make(map[string]ExecCallback)
+	services := make([]Component, 0, serviceCount)
 	for i := 0; i < serviceCount; i++ {
-		name := fmt.Sprintf("service%d", i)
-		services[name] = createSuccessService(name)
+		services = append(services, createSuccessService(fmt.Sprintf("service%d", i)))
+		services = append(services, Component{
+			Name: fmt.Sprintf("worker%d", i),
+			Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+		})
 	}
 
 	// Run in a goroutine so we can control shutdown
 	errCh := make(chan error)
 	go func() {
-		err := Run(ctx, 1*time.Second, services)
+		err := Run(ctx, 10*time.Second, services...)
 		errCh <- err
 	}()
 
@@ -325,6 +560,52 @@ func TestConcurrentStartup(t *testing.T) {
 	}
 }
 
+func TestRunReloadOnSIGHUP(t *testing.T) {
+	setupTestLogger()
+	recorder := &lifecycleRecorder{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	config := recorder.component("config")
+	config.Start = func(ctx context.Context) error {
+		close(started)
+		return nil
+	}
+	config.Reload = func(ctx context.Context) error {
+		recorder.record("reload config")
+		return errors.New("invalid configuration")
+	}
+	server := recorder.component("server")
+	server.DependsOn = []string{"config"}
+	server.Reload = func(ctx context.Context) error {
+		recorder.record("reload server")
+		return nil
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- Run(ctx, time.Second, server, config)
+	}()
+
+	// Failed reload keeps the service running
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	simulateSignal(syscall.SIGHUP)
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	if err := <-errCh; err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	expected := []string{"start server", "reload config", "reload server", "stop server", "stop config"}
+	if events := recorder.getEvents(); strings.Join(events, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected events %v, got: %v", expected, events)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
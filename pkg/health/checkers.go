@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"net"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Pinger is implemented by *sql.DB and *sqlx.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SQLChecker pings the database connection pool
+func SQLChecker(db Pinger) Checker {
+	return db.PingContext
+}
+
+// RedisChecker sends PING to standalone, cluster or sentinel client
+func RedisChecker(client goRedis.UniversalClient) Checker {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
+
+// MongoChecker pings the primary of the replica set
+func MongoChecker(client *mongo.Client) Checker {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}
+}
+
+// DialChecker opens a TCP connection to the address, it is used for dependency without
+// ping API such as OTLP collector
+func DialChecker(address string) Checker {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
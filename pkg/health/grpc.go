@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// watchInterval is how often Watch re-evaluates readiness
+const watchInterval = 5 * time.Second
+
+type grpcHealthServer struct {
+	healthPb.UnimplementedHealthServer
+	registry *Registry
+	services []string
+}
+
+// NewGRPCServer returns grpc.health.v1.Health implementation backed by readiness of the
+// registry. Empty service name reports the overall status, other than given services is unknown.
+func NewGRPCServer(registry *Registry, services ...string) healthPb.HealthServer {
+	return &grpcHealthServer{
+		registry: registry,
+		services: services,
+	}
+}
+
+func (s *grpcHealthServer) servingStatus(ctx context.Context, service string) healthPb.HealthCheckResponse_ServingStatus {
+	if service != "" && !slices.Contains(s.services, service) {
+		return healthPb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	if s.registry.Ready(ctx).IsUp() {
+		return healthPb.HealthCheckResponse_SERVING
+	}
+	return healthPb.HealthCheckResponse_NOT_SERVING
+}
+
+func (s *grpcHealthServer) Check(ctx context.Context, req *healthPb.HealthCheckRequest) (*healthPb.HealthCheckResponse, error) {
+	servingStatus := s.servingStatus(ctx, req.GetService())
+	if servingStatus == healthPb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	return &healthPb.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+func (s *grpcHealthServer) Watch(req *healthPb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthPb.HealthCheckResponse]) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	last := healthPb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		// Send only when serving status changes, as required by the protocol
+		if current := s.servingStatus(stream.Context(), req.GetService()); current != last {
+			if err := stream.Send(&healthPb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
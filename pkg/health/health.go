@@ -0,0 +1,159 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds a check that does not configure its own timeout
+const DefaultTimeout = 2 * time.Second
+
+// ErrShuttingDown is reported by readiness once graceful shutdown has started
+var ErrShuttingDown = errors.New("service is shutting down")
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Checker returns nil when the dependency is healthy
+type Checker func(ctx context.Context) error
+
+type Check struct {
+	Name    string
+	Checker Checker
+	Timeout time.Duration
+
+	// Critical check failure makes the service not ready, non critical failure is
+	// only reported in the detail
+	Critical bool
+}
+
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Reason string        `json:"reason,omitempty"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+// IsUp reports whether the service can receive traffic
+func (r Report) IsUp() bool {
+	return r.Status == StatusUp
+}
+
+// Registry keeps named dependency checks, it is safe for concurrent use
+type Registry struct {
+	mu           sync.RWMutex
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check, check with the same name replaces the previous one
+func (r *Registry) Register(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.checks {
+		if r.checks[i].Name == check.Name {
+			r.checks[i] = check
+			return
+		}
+	}
+	r.checks = append(r.checks, check)
+}
+
+// Shutdown flips readiness to failing so load balancer stops sending new traffic,
+// liveness is not affected.
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// Live reports whether the process is able to serve at all, it never runs dependency
+// checks so an outage of a dependency does not restart the service.
+func (r *Registry) Live(ctx context.Context) Report {
+	return Report{Status: StatusUp}
+}
+
+// Ready runs every check concurrently, the service is ready when no critical check
+// fails and shutdown has not started.
+func (r *Registry) Ready(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{Status: StatusDown, Reason: ErrShuttingDown.Error()}
+	}
+
+	r.mu.RLock()
+	checks := append([]Check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Critical && result.Status == StatusDown {
+			report.Status = StatusDown
+			report.Reason = "one or more critical checks failed"
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check.Checker(checkCtx)
+	}()
+
+	// Checker that ignores ctx is still bounded by the timeout
+	var err error
+	select {
+	case err = <-errCh:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+
+	result := CheckResult{
+		Name:     check.Name,
+		Status:   StatusUp,
+		Critical: check.Critical,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthPb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/health"
+)
+
+func healthy(ctx context.Context) error { return nil }
+
+func failing(ctx context.Context) error { return errors.New("connection refused") }
+
+func hanging(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestReady(t *testing.T) {
+	cases := []struct {
+		name     string
+		checks   []Check
+		expected Status
+	}{
+		{"no checks", nil, StatusUp},
+		{"all healthy", []Check{{Name: "db", Checker: healthy, Critical: true}}, StatusUp},
+		{"critical failure", []Check{{Name: "db", Checker: failing, Critical: true}}, StatusDown},
+		{"non critical failure", []Check{{Name: "vault", Checker: failing}}, StatusUp},
+		{"critical timeout", []Check{{Name: "redis", Checker: hanging, Timeout: 10 * time.Millisecond, Critical: true}}, StatusDown},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewRegistry()
+			for _, check := range tc.checks {
+				registry.Register(check)
+			}
+
+			report := registry.Ready(context.Background())
+			if report.Status != tc.expected {
+				t.Errorf("Expected status %s, got: %s", tc.expected, report.Status)
+			}
+			if len(report.Checks) != len(tc.checks) {
+				t.Errorf("Expected %d check results, got: %d", len(tc.checks), len(report.Checks))
+			}
+		})
+	}
+}
+
+func TestReadyReportsEveryCheck(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Check{Name: "db", Checker: healthy, Critical: true})
+	registry.Register(Check{Name: "vault", Checker: failing})
+
+	report := registry.Ready(context.Background())
+	if report.Checks[0].Name != "db" || report.Checks[0].Status != StatusUp {
+		t.Errorf("Expected db to be up, got: %+v", report.Checks[0])
+	}
+	if report.Checks[1].Name != "vault" || report.Checks[1].Status != StatusDown || report.Checks[1].Error != "connection refused" {
+		t.Errorf("Expected vault to be down with error, got: %+v", report.Checks[1])
+	}
+}
+
+func TestShutdownFlipsReadiness(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Check{Name: "db", Checker: healthy, Critical: true})
+	registry.Shutdown()
+
+	if report := registry.Ready(context.Background()); report.IsUp() {
+		t.Errorf("Expected not ready after shutdown, got: %+v", report)
+	}
+	if report := registry.Live(context.Background()); !report.IsUp() {
+		t.Errorf("Expected still alive after shutdown, got: %+v", report)
+	}
+}
+
+func TestGRPCServerCheck(t *testing.T) {
+	registry := NewRegistry()
+	server := NewGRPCServer(registry, "serviceuser.ServiceUser")
+
+	for _, service := range []string{"", "serviceuser.ServiceUser"} {
+		res, err := server.Check(context.Background(), &healthPb.HealthCheckRequest{Service: service})
+		if err != nil || res.GetStatus() != healthPb.HealthCheckResponse_SERVING {
+			t.Errorf("Expected %q to be serving, got: %v, %v", service, res, err)
+		}
+	}
+
+	if _, err := server.Check(context.Background(), &healthPb.HealthCheckRequest{Service: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for unknown service, got: %v", err)
+	}
+
+	registry.Shutdown()
+	res, err := server.Check(context.Background(), &healthPb.HealthCheckRequest{})
+	if err != nil || res.GetStatus() != healthPb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected not serving after shutdown, got: %v, %v", res, err)
+	}
+}
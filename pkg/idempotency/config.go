@@ -0,0 +1,26 @@
+package idempotency
+
+import "time"
+
+const (
+	defaultTTL     = 24 * time.Hour
+	defaultLockTTL = time.Minute
+)
+
+type Config struct {
+	// TTL of the stored response, retry after it is served as new request. Default is 24 hours.
+	TTL time.Duration
+	// LockTTL bounds how long key stays in-flight when replica crashes before the response
+	// is stored, it should be longer than the request timeout. Default is 1 minute.
+	LockTTL time.Duration
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = defaultLockTTL
+	}
+	return cfg
+}
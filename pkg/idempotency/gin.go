@@ -0,0 +1,154 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/xid"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+const maxKeyLength = 255
+
+// GinMiddleware makes mutating request with Idempotency-Key header safe to retry. The first
+// response is stored and replayed for retry with the same key and body, retry while the
+// first request is in progress gets 409 and reuse of the key with different body gets 422.
+// Keys are scoped per authenticated subject, so it must be placed after auth middleware.
+//
+// Server error is not stored, so the client can retry it. Request is served without
+// idempotency when the store fails.
+func GinMiddleware(store IStore, cfg Config) gin.HandlerFunc {
+	cfg = cfg.withDefaults()
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(errs.ToREST(ErrInvalidKey))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(errs.ToREST(errs.Validation("unable to read request body").Wrap(err)))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key = scopeOf(ctx) + ":" + key
+		record := Record{Token: xid.New().String(), Fingerprint: fingerprint(c.Request, body)}
+		existing, err := store.Acquire(ctx, key, record, cfg.LockTTL)
+		if err != nil {
+			slog.WarnContext(ctx, "[IDEMPOTENCY] store failed, request is served without idempotency", "error", err)
+			c.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				c.AbortWithStatusJSON(errs.ToREST(ErrKeyReused))
+			case !existing.Completed:
+				c.AbortWithStatusJSON(errs.ToREST(ErrRequestInFlight))
+			default:
+				replay(c, existing)
+			}
+			return
+		}
+
+		// Store is updated even when the request is cancelled
+		storeCtx := context.WithoutCancel(ctx)
+		defer func() {
+			if p := recover(); p != nil {
+				store.Release(storeCtx, key, record)
+				panic(p)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := store.Release(storeCtx, key, record); err != nil {
+				slog.WarnContext(ctx, "[IDEMPOTENCY] failed to release key", "error", err)
+			}
+			return
+		}
+
+		record.Completed = true
+		record.Status = recorder.Status()
+		record.Header = recorder.Header().Clone()
+		record.Body = recorder.body.Bytes()
+		if err := store.Complete(storeCtx, key, record, cfg.TTL); err != nil {
+			slog.WarnContext(ctx, "[IDEMPOTENCY] failed to store response", "error", err)
+		}
+	}
+}
+
+// replay writes the stored response, header already set by previous middleware
+// (e.g. rate limit) is kept
+func replay(c *gin.Context, record *Record) {
+	header := c.Writer.Header()
+	for name, values := range record.Header {
+		if _, ok := header[name]; !ok {
+			header[name] = values
+		}
+	}
+	header.Set(HeaderIdempotentReplayed, "true")
+
+	c.Writer.WriteHeader(record.Status)
+	c.Writer.Write(record.Body)
+	c.Abort()
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// scopeOf returns the owner of the key, anonymous clients share the same scope
+func scopeOf(ctx context.Context) string {
+	if subject, ok := auth.SubjectFromContext(ctx); ok {
+		return subject.UniqueId
+	}
+	return "anonymous"
+}
+
+// fingerprint identifies the request, so key reused for other request can be detected
+func fingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps copy of the response body
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
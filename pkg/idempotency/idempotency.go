@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// HeaderIdempotencyKey is the request header that carries the client generated key
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// HeaderIdempotentReplayed is set on response replayed from the store
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+var (
+	ErrInvalidKey      = errs.Validation("idempotency key must be at most 255 characters")
+	ErrRequestInFlight = errs.Conflict("request with the same idempotency key is in progress")
+	ErrKeyReused       = errs.Unprocessable("idempotency key is already used by different request")
+)
+
+// Record is the state of an idempotency key. The key is in-flight until the first
+// response is stored, Token identifies the request that owns the in-flight key.
+type Record struct {
+	Token       string      `json:"token"`
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// IStore keeps idempotency records, every method is atomic across replicas
+type IStore interface {
+	// Acquire stores in-flight record when key is new and returns nil, otherwise the
+	// existing record is returned. The in-flight record expires after ttl, so key of
+	// crashed request can be retried.
+	Acquire(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error)
+	// Complete replaces in-flight record with the response
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release deletes in-flight record owned by record.Token, so the request can be retried
+	Release(ctx context.Context, key string, record Record) error
+}
@@ -0,0 +1,131 @@
+package idempotency_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/idempotency"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis/redistest"
+)
+
+type testServer struct {
+	router  *gin.Engine
+	calls   atomic.Int64
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newTestServer(t *testing.T, cfg redis.RedisConfig) *testServer {
+	t.Helper()
+
+	client, err := redis.NewClient(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	gin.SetMode(gin.TestMode)
+	server := &testServer{router: gin.New()}
+	server.router.Use(GinMiddleware(NewRedisStore(client, redistest.Prefix()), Config{}))
+	server.router.POST("/users", func(c *gin.Context) {
+		calls := server.calls.Add(1)
+		if server.entered != nil {
+			server.entered <- struct{}{}
+			<-server.release
+		}
+		c.Header("Location", "/users/1")
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+	server.router.POST("/broken", func(c *gin.Context) {
+		server.calls.Add(1)
+		c.Status(http.StatusInternalServerError)
+	})
+	return server
+}
+
+func (s *testServer) do(path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGinMiddleware(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		key      string
+		body     string
+		status   int
+		calls    int64
+		replayed bool
+	}{
+		{name: "first request", path: "/users", key: "key-1", body: `{"a":1}`, status: http.StatusCreated, calls: 1},
+		{name: "retry is replayed", path: "/users", key: "key-1", body: `{"a":1}`, status: http.StatusCreated, calls: 1, replayed: true},
+		{name: "key reused with other body", path: "/users", key: "key-1", body: `{"a":2}`, status: http.StatusUnprocessableEntity, calls: 1},
+		{name: "request without key", path: "/users", body: `{"a":1}`, status: http.StatusCreated, calls: 2},
+		{name: "server error", path: "/broken", key: "key-2", status: http.StatusInternalServerError, calls: 3},
+		{name: "server error is retried", path: "/broken", key: "key-2", status: http.StatusInternalServerError, calls: 4},
+		{name: "invalid key", path: "/users", key: strings.Repeat("k", 256), status: http.StatusBadRequest, calls: 4},
+	}
+
+	for name, cfg := range redistest.Backends(t) {
+		t.Run(name, func(t *testing.T) {
+			server := newTestServer(t, cfg)
+
+			var first *httptest.ResponseRecorder
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					rec := server.do(tc.path, tc.key, tc.body)
+					if rec.Code != tc.status || server.calls.Load() != tc.calls {
+						t.Fatalf("Expected status %d after %d calls, got: %d after %d calls", tc.status, tc.calls, rec.Code, server.calls.Load())
+					}
+					if replayed := rec.Header().Get(HeaderIdempotentReplayed) == "true"; replayed != tc.replayed {
+						t.Errorf("Expected replayed %v, got: %v", tc.replayed, replayed)
+					}
+
+					if first == nil {
+						first = rec
+					} else if tc.replayed && (rec.Body.String() != first.Body.String() || rec.Header().Get("Location") != "/users/1") {
+						t.Errorf("Expected the first response, got: %v %s", rec.Header(), rec.Body.String())
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestGinMiddlewareInFlight(t *testing.T) {
+	for name, cfg := range redistest.Backends(t) {
+		t.Run(name, func(t *testing.T) {
+			server := newTestServer(t, cfg)
+			server.entered = make(chan struct{})
+			server.release = make(chan struct{})
+
+			done := make(chan *httptest.ResponseRecorder)
+			go func() { done <- server.do("/users", "key-1", `{}`) }()
+			<-server.entered
+
+			if rec := server.do("/users", "key-1", `{}`); rec.Code != http.StatusConflict {
+				t.Errorf("Expected concurrent duplicate to get 409, got: %d", rec.Code)
+			}
+
+			close(server.release)
+			if rec := <-done; rec.Code != http.StatusCreated {
+				t.Errorf("Expected the first request to succeed, got: %d", rec.Code)
+			}
+			if rec := server.do("/users", "key-1", `{}`); rec.Header().Get(HeaderIdempotentReplayed) != "true" {
+				t.Errorf("Expected retry after completion to be replayed, got: %d %v", rec.Code, rec.Header())
+			}
+		})
+	}
+}
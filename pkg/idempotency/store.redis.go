@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+)
+
+// idempotencyKeyFormat is <prefix>:idempotency:<key>
+const idempotencyKeyFormat = "%s:idempotency:%s"
+
+// acquireScript returns the existing record or stores ARGV[1] for ARGV[2] ms
+var acquireScript = goRedis.NewScript(`
+local existing = redis.call('GET', KEYS[1])
+if existing then
+	return existing
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`)
+
+// completeScript replaces in-flight record owned by token ARGV[1] with ARGV[2] for ARGV[3] ms
+var completeScript = goRedis.NewScript(`
+local existing = redis.call('GET', KEYS[1])
+if not existing or cjson.decode(existing)['token'] ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// releaseScript deletes in-flight record owned by token ARGV[1]
+var releaseScript = goRedis.NewScript(`
+local existing = redis.call('GET', KEYS[1])
+if not existing then
+	return 0
+end
+local record = cjson.decode(existing)
+if record['token'] ~= ARGV[1] or record['completed'] then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+
+var _ IStore = (*redisStore)(nil)
+
+type redisStore struct {
+	prefix string
+	client goRedis.UniversalClient
+}
+
+// NewRedisStore returns IStore backed by Redis, prefix is used to namespace the keys
+// (e.g. application name)
+func NewRedisStore(client goRedis.UniversalClient, prefix string) IStore {
+	return &redisStore{
+		prefix: prefix,
+		client: client,
+	}
+}
+
+func (s *redisStore) key(key string) string {
+	return fmt.Sprintf(idempotencyKeyFormat, s.prefix, key)
+}
+
+func (s *redisStore) Acquire(ctx context.Context, key string, record Record, ttl time.Duration) (*Record, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := acquireScript.Run(ctx, s.client, []string{s.key(key)}, value, ttl.Milliseconds()).Text()
+	if err != nil {
+		if errors.Is(err, goRedis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to acquire idempotency key: %w", err)
+	}
+
+	var existingRecord Record
+	if err := json.Unmarshal([]byte(existing), &existingRecord); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	return &existingRecord, nil
+}
+
+func (s *redisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := completeScript.Run(ctx, s.client, []string{s.key(key)}, record.Token, value, ttl.Milliseconds()).Err(); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *redisStore) Release(ctx context.Context, key string, record Record) error {
+	if err := releaseScript.Run(ctx, s.client, []string{s.key(key)}, record.Token).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// Event is a domain event waiting in the outbox. Id is unique for every event and
+// stays the same when the event is published more than once, so consumer can use it
+// to drop duplicates of the at-least-once delivery.
+type Event struct {
+	Id          string
+	Type        string
+	AggregateId string
+	Payload     json.RawMessage
+	OccurredAt  time.Time
+}
+
+// NewEvent returns event with new id and JSON encoded payload
+func NewEvent(eventType, aggregateId string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Id:          xid.New().String(),
+		Type:        eventType,
+		AggregateId: aggregateId,
+		Payload:     data,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+type IStore interface {
+	// Add writes events to the outbox, it joins the transaction of ctx so events are
+	// committed together with the state change that emits them
+	Add(ctx context.Context, events ...Event) error
+
+	// ProcessPending locks up to limit pending events in occurrence order and passes
+	// them to handle. The first published events, as many as returned by handle, are
+	// marked as published. Events locked by other relay are skipped.
+	ProcessPending(ctx context.Context, limit int, handle func(ctx context.Context, events []Event) (int, error)) (int, error)
+}
+
+// IPublisher delivers event to the message broker
+type IPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,146 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jmoiron/sqlx"
+	goRedis "github.com/redis/go-redis/v9"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/outbox"
+	sqlPkg "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+// memoryStore is an in-memory IStore, published events are removed from pending
+type memoryStore struct {
+	pending []Event
+}
+
+func (s *memoryStore) Add(ctx context.Context, events ...Event) error {
+	s.pending = append(s.pending, events...)
+	return nil
+}
+
+func (s *memoryStore) ProcessPending(ctx context.Context, limit int, handle func(ctx context.Context, events []Event) (int, error)) (int, error) {
+	batch := s.pending[:min(limit, len(s.pending))]
+	published, err := handle(ctx, batch)
+	s.pending = s.pending[published:]
+	return published, err
+}
+
+// flakyPublisher fails once for every event id in failOnce
+type flakyPublisher struct {
+	failOnce  map[string]bool
+	published []string
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event Event) error {
+	if p.failOnce[event.Id] {
+		delete(p.failOnce, event.Id)
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.Id)
+	return nil
+}
+
+func newEvents(t *testing.T, n int) []Event {
+	t.Helper()
+
+	events := make([]Event, n)
+	for i := range events {
+		event, err := NewEvent("user.signed_up", "uid", map[string]int{"n": i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events[i] = event
+	}
+	return events
+}
+
+func TestRelayDrain(t *testing.T) {
+	events := newEvents(t, 5)
+	store := &memoryStore{}
+	store.Add(context.Background(), events...)
+	publisher := &flakyPublisher{failOnce: map[string]bool{events[3].Id: true}}
+	relay := NewRelay(store, publisher, RelayConfig{BatchSize: 2})
+
+	// Relay stops at the failed event and keeps it pending to preserve the order
+	published, err := relay.Drain(context.Background())
+	if err == nil || published != 3 || len(store.pending) != 2 {
+		t.Fatalf("Expected 3 published and failure, got: %d, %v, pending %d", published, err, len(store.pending))
+	}
+
+	published, err = relay.Drain(context.Background())
+	if err != nil || published != 2 || len(store.pending) != 0 {
+		t.Fatalf("Expected the rest to be published, got: %d, %v, pending %d", published, err, len(store.pending))
+	}
+
+	for i, event := range events {
+		if publisher.published[i] != event.Id {
+			t.Errorf("Expected event %d to be published in order, got: %v", i, publisher.published)
+		}
+	}
+}
+
+func TestSQLStoreProcessPending(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	db := sqlx.NewDb(mockDB, "pgx")
+	store, err := NewSQLStore(db, sqlPkg.NewTxManager(db, sqlPkg.TxOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "occurred_at"}).
+			AddRow("e1", "user.signed_up", "uid-1", []byte(`{}`), now).
+			AddRow("e2", "user.updated", "uid-1", []byte(`{}`), now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE outbox_events SET published_at = $1 WHERE id IN ($2)")).
+		WithArgs(sqlmock.AnyArg(), "e1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Event published before the failure stays marked
+	errPublish := errors.New("broker unavailable")
+	published, err := store.ProcessPending(context.Background(), 10, func(ctx context.Context, events []Event) (int, error) {
+		return 1, errPublish
+	})
+	if !errors.Is(err, errPublish) || published != 1 {
+		t.Errorf("Expected 1 published with publish error, got: %d, %v", published, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedisStreamPublisher(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := goRedis.NewClient(&goRedis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	event := newEvents(t, 1)[0]
+	if err := NewRedisStreamPublisher(client, "app:user_events", 0).Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := client.XRange(context.Background(), "app:user_events", "-", "+").Result()
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Expected 1 stream message, got: %v, %v", messages, err)
+	}
+	values := messages[0].Values
+	if values["id"] != event.Id || values["type"] != event.Type || values["payload"] != string(event.Payload) {
+		t.Errorf("Expected message to carry the event, got: %v", values)
+	}
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+)
+
+var (
+	_ IPublisher = (*redisStreamPublisher)(nil)
+	_ IPublisher = (*logPublisher)(nil)
+)
+
+type redisStreamPublisher struct {
+	client goRedis.UniversalClient
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamPublisher appends every event to the stream, the stream is trimmed
+// approximately to maxLen entries when maxLen is positive
+func NewRedisStreamPublisher(client goRedis.UniversalClient, stream string, maxLen int64) IPublisher {
+	return &redisStreamPublisher{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (p *redisStreamPublisher) Publish(ctx context.Context, event Event) error {
+	args := &goRedis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]any{
+			"id":           event.Id,
+			"type":         event.Type,
+			"aggregate_id": event.AggregateId,
+			"payload":      string(event.Payload),
+			"occurred_at":  event.OccurredAt.Format(time.RFC3339Nano),
+		},
+	}
+	if p.maxLen > 0 {
+		args.MaxLen = p.maxLen
+		args.Approx = true
+	}
+
+	return p.client.XAdd(ctx, args).Err()
+}
+
+type logPublisher struct{}
+
+// NewLogPublisher logs every event, it is used in development without message broker
+func NewLogPublisher() IPublisher {
+	return logPublisher{}
+}
+
+func (logPublisher) Publish(ctx context.Context, event Event) error {
+	slog.InfoContext(ctx, "[Outbox] event published",
+		"id", event.Id,
+		"type", event.Type,
+		"aggregate_id", event.AggregateId,
+		"payload", string(event.Payload),
+	)
+	return nil
+}
@@ -0,0 +1,88 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Default relay polling setup
+const (
+	DefaultRelayInterval  = time.Second
+	DefaultRelayBatchSize = 100
+)
+
+type RelayConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Relay moves pending events from the outbox to the publisher. Event is marked as
+// published only after the publisher accepted it, so it is delivered at least once.
+type Relay struct {
+	store     IStore
+	publisher IPublisher
+	cfg       RelayConfig
+}
+
+func NewRelay(store IStore, publisher IPublisher, cfg RelayConfig) *Relay {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultRelayInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultRelayBatchSize
+	}
+
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. Failure is logged and retried on the
+// next poll, so unavailable broker does not stop the service.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Drain(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "[Outbox] failed to relay events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain publishes pending events batch by batch until the outbox is empty
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	var total int
+	for ctx.Err() == nil {
+		published, err := r.store.ProcessPending(ctx, r.cfg.BatchSize, r.publish)
+		total += published
+		if err != nil {
+			return total, err
+		}
+		if published < r.cfg.BatchSize {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// publish stops at the first failure so events of the same aggregate keep their order
+func (r *Relay) publish(ctx context.Context, events []Event) (int, error) {
+	for i, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			return i, fmt.Errorf("publish event %s: %w", event.Id, err)
+		}
+	}
+	return len(events), nil
+}
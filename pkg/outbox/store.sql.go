@@ -0,0 +1,131 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	sqlPkg "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+var _ IStore = (*sqlStore)(nil)
+
+// eventRow is the outbox_events row, payload is scanned as bytes because drivers
+// return JSON column as string or bytes
+type eventRow struct {
+	Id          string    `db:"id"`
+	Type        string    `db:"event_type"`
+	AggregateId string    `db:"aggregate_id"`
+	Payload     []byte    `db:"payload"`
+	OccurredAt  time.Time `db:"occurred_at"`
+}
+
+type sqlStore struct {
+	db        *sqlx.DB
+	dialect   sqlPkg.IDialect
+	txManager sqlPkg.ITxManager
+}
+
+// NewSQLStore returns outbox stored in outbox_events table of db
+func NewSQLStore(db *sqlx.DB, txManager sqlPkg.ITxManager) (IStore, error) {
+	dialect, err := sqlPkg.DialectOf(db.DriverName())
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlStore{
+		db:        db,
+		dialect:   dialect,
+		txManager: txManager,
+	}, nil
+}
+
+func (s *sqlStore) Add(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]eventRow, len(events))
+	for i, event := range events {
+		rows[i] = eventRow{
+			Id:          event.Id,
+			Type:        event.Type,
+			AggregateId: event.AggregateId,
+			Payload:     event.Payload,
+			OccurredAt:  event.OccurredAt,
+		}
+	}
+
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_id, payload, occurred_at)
+		VALUES (:id, :event_type, :aggregate_id, :payload, :occurred_at)
+	`
+	if _, err := sqlPkg.Executor(ctx, s.db).NamedExecContext(ctx, query, rows); err != nil {
+		return fmt.Errorf("failed to add outbox events: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlStore) ProcessPending(ctx context.Context, limit int, handle func(ctx context.Context, events []Event) (int, error)) (int, error) {
+	// Publish error is returned after commit, so events published before it stay marked
+	var (
+		published int
+		handleErr error
+	)
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		executor := sqlPkg.Executor(ctx, s.db)
+
+		// SKIP LOCKED lets every replica run the relay without publishing the same batch
+		var rows []eventRow
+		query := s.dialect.Rebind(`
+			SELECT id, event_type, aggregate_id, payload, occurred_at
+			FROM outbox_events
+			WHERE published_at IS NULL
+			ORDER BY occurred_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		`)
+		if err := executor.SelectContext(ctx, &rows, query, limit); err != nil {
+			return fmt.Errorf("failed to retrieve pending outbox events: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		events := make([]Event, len(rows))
+		for i, row := range rows {
+			events[i] = Event{
+				Id:          row.Id,
+				Type:        row.Type,
+				AggregateId: row.AggregateId,
+				Payload:     row.Payload,
+				OccurredAt:  row.OccurredAt,
+			}
+		}
+
+		published, handleErr = handle(ctx, events)
+		if published == 0 {
+			return nil
+		}
+
+		ids := make([]string, published)
+		for i := range ids {
+			ids[i] = events[i].Id
+		}
+		update, args, err := sqlx.In(`UPDATE outbox_events SET published_at = ? WHERE id IN (?)`, time.Now().UTC(), ids)
+		if err != nil {
+			return err
+		}
+		if _, err := executor.ExecContext(ctx, s.dialect.Rebind(update), args...); err != nil {
+			return fmt.Errorf("failed to mark outbox events as published: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		// Marks are rolled back, the events are published again by the next poll
+		return 0, err
+	}
+
+	return published, handleErr
+}
@@ -0,0 +1,17 @@
+package ratelimit
+
+type RateLimitConfig struct {
+	RateLimitEnabled bool `mapstructure:"RATELIMIT_ENABLED" reload:"true"`
+	// RateLimitPolicy maps route to limit with format "route=limit/window[,algorithm];...",
+	// route is "METHOD /path" of REST route, full gRPC method name or "*" for the default.
+	// Algorithm is sliding_window (default) or token_bucket.
+	//
+	//	POST /api/v1/users/signup=5/1m;/serviceuser.ServiceUser/SignUp=5/1m;*=100/1s,token_bucket
+	RateLimitPolicy string `mapstructure:"RATELIMIT_POLICY" reload:"true"`
+	// RateLimitAPIKeyHeader opts in to limit per API key, the header (or gRPC metadata)
+	// identifies client only when it carries a key of RateLimitAPIKeys
+	RateLimitAPIKeyHeader string `mapstructure:"RATELIMIT_API_KEY_HEADER"`
+	// RateLimitAPIKeys are the known API keys with format "client=sha256;...", sha256 is
+	// hex encoded SHA-256 of the key so the key itself is not in the configuration
+	RateLimitAPIKeys string `mapstructure:"RATELIMIT_API_KEYS" secret:"true"`
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// GinMiddleware limits request by the rule of its route ("METHOD /path") and reports the
+// limit with RateLimit-* headers. Route without rule is not limited, and request is allowed
+// when the limiter fails, so outage of the limiter never takes the API down.
+func GinMiddleware(limiter ILimiter, policy IPolicy, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		rule, ok := policy.RuleOf(route)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), route+":"+key(c), rule)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "[RATELIMIT] limiter failed, request is allowed", "route", route, "error", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Policy", rule.String())
+		header.Set("RateLimit-Limit", strconv.Itoa(rule.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", seconds(result.ResetAfter))
+		if !result.Allowed {
+			header.Set("Retry-After", seconds(result.RetryAfter))
+			c.AbortWithStatusJSON(errs.ToREST(ErrRateLimited))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds, the unit of RateLimit-Reset and Retry-After
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// UnaryServerInterceptor limits call by the rule of its full method name, rejected call
+// returns codes.ResourceExhausted with errdetails.RetryInfo
+func UnaryServerInterceptor(limiter ILimiter, policy IPolicy, key GRPCKeyFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := allowGRPC(ctx, limiter, policy, key, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the stream version of UnaryServerInterceptor, the limit is
+// counted once per stream
+func StreamServerInterceptor(limiter ILimiter, policy IPolicy, key GRPCKeyFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allowGRPC(ss.Context(), limiter, policy, key, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func allowGRPC(ctx context.Context, limiter ILimiter, policy IPolicy, key GRPCKeyFunc, method string) error {
+	rule, ok := policy.RuleOf(method)
+	if !ok {
+		return nil
+	}
+
+	result, err := limiter.Allow(ctx, method+":"+key(ctx), rule)
+	if err != nil {
+		slog.WarnContext(ctx, "[RATELIMIT] limiter failed, call is allowed", "method", method, "error", err)
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+
+	st := errs.ToGRPCStatus(ErrRateLimited)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(result.RetryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// KeyFunc identifies the client of REST request
+type KeyFunc func(c *gin.Context) string
+
+// GRPCKeyFunc identifies the client of gRPC call
+type GRPCKeyFunc func(ctx context.Context) string
+
+// KeyByIP identifies client by its IP. X-Forwarded-For is only used when the request comes
+// from trusted proxy of the engine (see gin.Engine.SetTrustedProxies), otherwise it is the
+// remote address of the connection.
+func KeyByIP() KeyFunc {
+	return func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	}
+}
+
+// KeyBySubject identifies client by authenticated subject and falls back to IP for anonymous
+// request, it must be placed after auth middleware
+func KeyBySubject() KeyFunc {
+	byIP := KeyByIP()
+	return func(c *gin.Context) string {
+		if subject, ok := auth.SubjectFromContext(c.Request.Context()); ok {
+			return "subject:" + subject.UniqueId
+		}
+		return byIP(c)
+	}
+}
+
+// IAPIKeyVerifier returns the client owning apiKey, ok is false when the key is unknown
+type IAPIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, apiKey string) (client string, ok bool)
+}
+
+// APIKeys verifies API keys by their SHA-256, see RateLimitConfig.RateLimitAPIKeys
+type APIKeys map[string]string
+
+// ParseAPIKeys parses "client=sha256;..." where sha256 is hex encoded SHA-256 of the key
+func ParseAPIKeys(spec string) (APIKeys, error) {
+	keys := make(APIKeys)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		client, hash, found := strings.Cut(entry, "=")
+		client, hash = strings.TrimSpace(client), strings.ToLower(strings.TrimSpace(hash))
+		if decoded, err := hex.DecodeString(hash); !found || client == "" || err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("ratelimit: invalid api key of client %q, expected client=sha256", client)
+		}
+		keys[hash] = client
+	}
+	return keys, nil
+}
+
+func (k APIKeys) VerifyAPIKey(ctx context.Context, apiKey string) (string, bool) {
+	sum := sha256.Sum256([]byte(apiKey))
+	client, ok := k[hex.EncodeToString(sum[:])]
+	return client, ok
+}
+
+// KeyByAPIKey identifies client by API key in header once verifier knows the key, request
+// without key or with unknown key falls back to KeyBySubject. Unknown key is ignored, so
+// rotating made up keys does not escape the limit of the IP.
+func KeyByAPIKey(header string, verifier IAPIKeyVerifier) KeyFunc {
+	bySubject := KeyBySubject()
+	return func(c *gin.Context) string {
+		if client, ok := verifyAPIKey(c.Request.Context(), verifier, header, c.GetHeader(header)); ok {
+			return "api_key:" + client
+		}
+		return bySubject(c)
+	}
+}
+
+// GRPCKeyByIP identifies client by peer address
+func GRPCKeyByIP() GRPCKeyFunc {
+	return func(ctx context.Context) string {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return "ip:unknown"
+		}
+
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			return "ip:" + p.Addr.String()
+		}
+		return "ip:" + host
+	}
+}
+
+// GRPCKeyBySubject is gRPC version of KeyBySubject, it must be chained after auth interceptor
+func GRPCKeyBySubject() GRPCKeyFunc {
+	byIP := GRPCKeyByIP()
+	return func(ctx context.Context) string {
+		if subject, ok := auth.SubjectFromContext(ctx); ok {
+			return "subject:" + subject.UniqueId
+		}
+		return byIP(ctx)
+	}
+}
+
+// GRPCKeyByAPIKey is gRPC version of KeyByAPIKey, header is read from incoming metadata
+func GRPCKeyByAPIKey(header string, verifier IAPIKeyVerifier) GRPCKeyFunc {
+	bySubject := GRPCKeyBySubject()
+	return func(ctx context.Context) string {
+		var apiKey string
+		if md, ok := metadata.FromIncomingContext(ctx); ok && header != "" {
+			if values := md.Get(header); len(values) > 0 {
+				apiKey = values[0]
+			}
+		}
+		if client, ok := verifyAPIKey(ctx, verifier, header, apiKey); ok {
+			return "api_key:" + client
+		}
+		return bySubject(ctx)
+	}
+}
+
+func verifyAPIKey(ctx context.Context, verifier IAPIKeyVerifier, header, apiKey string) (string, bool) {
+	if header == "" || apiKey == "" || verifier == nil {
+		return "", false
+	}
+	return verifier.VerifyAPIKey(ctx, apiKey)
+}
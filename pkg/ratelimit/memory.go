@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often expired limits are removed from memory
+const memorySweepInterval = time.Minute
+
+var _ ILimiter = (*memoryLimiter)(nil)
+
+// memoryLimit is state of both algorithms, only the fields of its algorithm are used
+type memoryLimit struct {
+	requests   []time.Time // sliding window
+	tokens     float64     // token bucket
+	refilledAt time.Time   // token bucket
+	expireAt   time.Time
+}
+
+// memoryLimiter limits request per replica, it is meant for fallback, tests and
+// local development with a single replica
+type memoryLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]*memoryLimit
+	sweptAt time.Time
+	now     func() time.Time
+}
+
+// NewMemoryLimiter returns limiter that keeps the limits in process memory
+func NewMemoryLimiter() ILimiter {
+	return &memoryLimiter{
+		limits: make(map[string]*memoryLimit),
+		now:    time.Now,
+	}
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	key = string(rule.Algorithm) + ":" + key
+	limit, ok := l.limits[key]
+	if !ok || !now.Before(limit.expireAt) {
+		limit = &memoryLimit{tokens: float64(rule.Limit), refilledAt: now}
+		l.limits[key] = limit
+	}
+	limit.expireAt = now.Add(rule.Window)
+
+	if rule.Algorithm == AlgorithmTokenBucket {
+		return allowTokenBucket(limit, rule, now), nil
+	}
+	return allowSlidingWindow(limit, rule, now), nil
+}
+
+// sweep removes expired limits, it runs at most once every memorySweepInterval
+func (l *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < memorySweepInterval {
+		return
+	}
+
+	for key, limit := range l.limits {
+		if !now.Before(limit.expireAt) {
+			delete(l.limits, key)
+		}
+	}
+	l.sweptAt = now
+}
+
+func allowSlidingWindow(limit *memoryLimit, rule Rule, now time.Time) Result {
+	windowStart := now.Add(-rule.Window)
+	active := 0
+	for active < len(limit.requests) && !limit.requests[active].After(windowStart) {
+		active++
+	}
+	limit.requests = limit.requests[active:]
+
+	result := Result{}
+	if len(limit.requests) < rule.Limit {
+		limit.requests = append(limit.requests, now)
+		result.Allowed = true
+	}
+
+	result.Remaining = rule.Limit - len(limit.requests)
+	result.ResetAfter = limit.requests[0].Add(rule.Window).Sub(now)
+	if !result.Allowed {
+		result.RetryAfter = result.ResetAfter
+	}
+	return result
+}
+
+func allowTokenBucket(limit *memoryLimit, rule Rule, now time.Time) Result {
+	// tokens refilled per nanosecond
+	rate := float64(rule.Limit) / float64(rule.Window)
+	limit.tokens = math.Min(float64(rule.Limit), limit.tokens+float64(now.Sub(limit.refilledAt))*rate)
+	limit.refilledAt = now
+
+	result := Result{}
+	if limit.tokens >= 1 {
+		limit.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - limit.tokens) / rate))
+	}
+
+	result.Remaining = int(limit.tokens)
+	result.ResetAfter = time.Duration(math.Ceil((float64(rule.Limit) - limit.tokens) / rate))
+	return result
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// RouteDefault is the policy route applied to every route without its own rule
+const RouteDefault = "*"
+
+// IPolicy returns rule of route, it is implemented by Policy and DynamicPolicy
+type IPolicy interface {
+	RuleOf(route string) (Rule, bool)
+}
+
+// Policy maps route to its rule, see RateLimitConfig.RateLimitPolicy
+type Policy map[string]Rule
+
+// RuleOf returns rule of route, the default rule is used when route has no rule
+func (p Policy) RuleOf(route string) (Rule, bool) {
+	if rule, ok := p[route]; ok {
+		return rule, true
+	}
+	rule, ok := p[RouteDefault]
+	return rule, ok
+}
+
+// DynamicPolicy is a policy which can be replaced while serving, e.g. when configuration
+// is reloaded
+type DynamicPolicy struct {
+	policy atomic.Pointer[Policy]
+}
+
+func NewDynamicPolicy(policy Policy) *DynamicPolicy {
+	p := new(DynamicPolicy)
+	p.Store(policy)
+	return p
+}
+
+// Store replaces the policy, in-flight request keeps the rule it has read
+func (p *DynamicPolicy) Store(policy Policy) {
+	p.policy.Store(&policy)
+}
+
+func (p *DynamicPolicy) RuleOf(route string) (Rule, bool) {
+	return p.policy.Load().RuleOf(route)
+}
+
+// ParsePolicy parses RateLimitPolicy, empty policy limits nothing
+func ParsePolicy(policy string) (Policy, error) {
+	rules := make(Policy)
+	for _, entry := range strings.Split(policy, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		separator := strings.LastIndex(entry, "=")
+		route := strings.TrimSpace(entry[:max(separator, 0)])
+		if separator < 0 || route == "" {
+			return nil, fmt.Errorf("ratelimit: invalid policy rule %q", entry)
+		}
+
+		rule, err := parseRule(entry[separator+1:])
+		if err != nil {
+			return nil, fmt.Errorf("ratelimit: invalid policy rule %q: %w", entry, err)
+		}
+		rules[route] = rule
+	}
+
+	return rules, nil
+}
+
+// parseRule parses "limit/window[,algorithm]"
+func parseRule(value string) (Rule, error) {
+	value, algorithm, _ := strings.Cut(strings.TrimSpace(value), ",")
+	limit, window, found := strings.Cut(value, "/")
+	if !found {
+		return Rule{}, errors.New("expected limit/window")
+	}
+
+	rule := Rule{Algorithm: Algorithm(strings.TrimSpace(algorithm))}
+	if rule.Algorithm == "" {
+		rule.Algorithm = AlgorithmSlidingWindow
+	}
+	if rule.Algorithm != AlgorithmSlidingWindow && rule.Algorithm != AlgorithmTokenBucket {
+		return Rule{}, fmt.Errorf("unknown algorithm %s", rule.Algorithm)
+	}
+
+	var err error
+	if rule.Limit, err = strconv.Atoi(strings.TrimSpace(limit)); err != nil || rule.Limit <= 0 {
+		return Rule{}, fmt.Errorf("limit must be positive number: %s", limit)
+	}
+	if rule.Window, err = time.ParseDuration(strings.TrimSpace(window)); err != nil || rule.Window <= 0 {
+		return Rule{}, fmt.Errorf("window must be positive duration: %s", window)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// ErrRateLimited is returned to the client that exceeds the limit of the route
+var ErrRateLimited = errs.TooManyRequests("too many requests")
+
+// Algorithm of the limit
+type Algorithm string
+
+const (
+	// AlgorithmSlidingWindow allows Limit requests in any Window long period
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+	// AlgorithmTokenBucket allows burst of Limit requests and refills Limit tokens every Window
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+)
+
+// Rule is the limit of a route
+type Rule struct {
+	Algorithm Algorithm
+	Limit     int
+	Window    time.Duration
+}
+
+// String returns the rule in RateLimit-Policy header format (e.g. 10;w=60)
+func (r Rule) String() string {
+	return fmt.Sprintf("%d;w=%d", r.Limit, int64(r.Window.Seconds()))
+}
+
+// Result of a single request, RetryAfter is set only when the request is rejected
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// ILimiter counts request of key against rule, every implementation is safe for
+// concurrent use by multiple replicas or goroutines
+type ILimiter interface {
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+var _ ILimiter = (*fallbackLimiter)(nil)
+
+// fallbackLimiter uses fallback while primary is failing, e.g. in-memory limiter while
+// Redis is unavailable, so limit is still enforced per replica
+type fallbackLimiter struct {
+	primary  ILimiter
+	fallback ILimiter
+}
+
+// NewFallbackLimiter returns limiter that falls back to fallback when primary fails
+func NewFallbackLimiter(primary, fallback ILimiter) ILimiter {
+	return &fallbackLimiter{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	result, err := l.primary.Allow(ctx, key, rule)
+	if err == nil {
+		return result, nil
+	}
+
+	slog.WarnContext(ctx, "[RATELIMIT] primary limiter failed, using fallback", "error", err)
+	return l.fallback.Allow(ctx, key, rule)
+}
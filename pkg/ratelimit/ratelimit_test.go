@@ -0,0 +1,273 @@
+package ratelimit_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	goRedis "github.com/redis/go-redis/v9"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/ratelimit"
+)
+
+func TestParsePolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   string
+		expected Policy
+		invalid  bool
+	}{
+		{
+			name:   "routes with default algorithm",
+			policy: "POST /api/v1/users/signup=5/1m; /serviceuser.ServiceUser/SignUp = 5/1m ;*=100/1s,token_bucket",
+			expected: Policy{
+				"POST /api/v1/users/signup":       {Algorithm: AlgorithmSlidingWindow, Limit: 5, Window: time.Minute},
+				"/serviceuser.ServiceUser/SignUp": {Algorithm: AlgorithmSlidingWindow, Limit: 5, Window: time.Minute},
+				RouteDefault:                      {Algorithm: AlgorithmTokenBucket, Limit: 100, Window: time.Second},
+			},
+		},
+		{name: "empty", policy: "", expected: Policy{}},
+		{name: "missing route", policy: "=5/1m", invalid: true},
+		{name: "missing window", policy: "*=5", invalid: true},
+		{name: "zero limit", policy: "*=0/1m", invalid: true},
+		{name: "invalid window", policy: "*=5/minute", invalid: true},
+		{name: "unknown algorithm", policy: "*=5/1m,leaky_bucket", invalid: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tc.policy)
+			if (err != nil) != tc.invalid {
+				t.Fatalf("Expected invalid %v, got: %v", tc.invalid, err)
+			}
+			if len(policy) != len(tc.expected) {
+				t.Fatalf("Expected %d rules, got: %v", len(tc.expected), policy)
+			}
+			for route, rule := range tc.expected {
+				if policy[route] != rule {
+					t.Errorf("Expected rule %+v of %q, got: %+v", rule, route, policy[route])
+				}
+			}
+		})
+	}
+}
+
+func newLimiters(t *testing.T) map[string]ILimiter {
+	t.Helper()
+
+	client := goRedis.NewClient(&goRedis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]ILimiter{
+		"redis":  NewRedisLimiter(client, "test"),
+		"memory": NewMemoryLimiter(),
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	ctx := context.Background()
+	for name, limiter := range newLimiters(t) {
+		for _, algorithm := range []Algorithm{AlgorithmSlidingWindow, AlgorithmTokenBucket} {
+			t.Run(name+"/"+string(algorithm), func(t *testing.T) {
+				rule := Rule{Algorithm: algorithm, Limit: 3, Window: time.Hour}
+				for i := range rule.Limit {
+					result, err := limiter.Allow(ctx, "client-1", rule)
+					if err != nil || !result.Allowed || result.Remaining != rule.Limit-i-1 {
+						t.Fatalf("Expected request %d to be allowed, got: %+v, %v", i, result, err)
+					}
+					if result.ResetAfter <= 0 || result.ResetAfter > rule.Window {
+						t.Errorf("Expected reset within the window, got: %s", result.ResetAfter)
+					}
+				}
+
+				result, err := limiter.Allow(ctx, "client-1", rule)
+				if err != nil || result.Allowed || result.Remaining != 0 {
+					t.Fatalf("Expected request over the limit to be rejected, got: %+v, %v", result, err)
+				}
+				if result.RetryAfter <= 0 || result.RetryAfter > rule.Window {
+					t.Errorf("Expected retry within the window, got: %s", result.RetryAfter)
+				}
+
+				if result, err := limiter.Allow(ctx, "client-2", rule); err != nil || !result.Allowed {
+					t.Errorf("Expected other client to have its own limit, got: %+v, %v", result, err)
+				}
+			})
+		}
+	}
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := goRedis.NewClient(&goRedis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	server.Close()
+
+	limiter := NewFallbackLimiter(NewRedisLimiter(client, "test"), NewMemoryLimiter())
+	rule := Rule{Algorithm: AlgorithmSlidingWindow, Limit: 1, Window: time.Hour}
+	if result, err := limiter.Allow(context.Background(), "client", rule); err != nil || !result.Allowed {
+		t.Fatalf("Expected fallback to allow first request, got: %+v, %v", result, err)
+	}
+	if result, err := limiter.Allow(context.Background(), "client", rule); err != nil || result.Allowed {
+		t.Errorf("Expected fallback to enforce the limit, got: %+v, %v", result, err)
+	}
+}
+
+func TestGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policy := Policy{"POST /signup": {Algorithm: AlgorithmSlidingWindow, Limit: 1, Window: time.Minute}}
+	router := gin.New()
+	if err := router.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	apiKeys, err := ParseAPIKeys("partner=" + sha256Hex("known-key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.Use(GinMiddleware(NewMemoryLimiter(), policy, KeyByAPIKey("X-API-Key", apiKeys)))
+	router.POST("/signup", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		remoteAddr string
+		header     map[string]string
+		status     int
+	}{
+		{"first request", http.MethodPost, "/signup", "192.0.2.1:1234", nil, http.StatusCreated},
+		{"over the limit", http.MethodPost, "/signup", "192.0.2.1:1234", nil, http.StatusTooManyRequests},
+		{"unverified api key is not a client", http.MethodPost, "/signup", "192.0.2.1:1234", map[string]string{"X-API-Key": "secret"}, http.StatusTooManyRequests},
+		{"verified api key is a client", http.MethodPost, "/signup", "192.0.2.1:1234", map[string]string{"X-API-Key": "known-key"}, http.StatusCreated},
+		{"verified api key from other IP", http.MethodPost, "/signup", "198.51.100.9:1234", map[string]string{"X-API-Key": "known-key"}, http.StatusTooManyRequests},
+		{"forwarded for of untrusted proxy is ignored", http.MethodPost, "/signup", "192.0.2.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.7"}, http.StatusTooManyRequests},
+		{"other client", http.MethodPost, "/signup", "198.51.100.2:1234", nil, http.StatusCreated},
+		{"client behind trusted proxy", http.MethodPost, "/signup", "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.7"}, http.StatusCreated},
+		{"route without rule", http.MethodGet, "/users", "192.0.2.1:1234", nil, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.RemoteAddr = tc.remoteAddr
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("Expected status %d, got: %d", tc.status, rec.Code)
+			}
+			if tc.path == "/signup" && (rec.Header().Get("RateLimit-Limit") != "1" || rec.Header().Get("RateLimit-Policy") != "1;w=60") {
+				t.Errorf("Expected RateLimit headers, got: %v", rec.Header())
+			}
+			if retryAfter := rec.Header().Get("Retry-After"); (tc.status == http.StatusTooManyRequests) != (retryAfter != "") {
+				t.Errorf("Expected Retry-After only on rejected request, got: %q", retryAfter)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	policy := Policy{RouteDefault: {Algorithm: AlgorithmTokenBucket, Limit: 1, Window: time.Minute}}
+	interceptor := UnaryServerInterceptor(NewMemoryLimiter(), policy, GRPCKeyBySubject())
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/SignUp"}
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("Expected first call to be allowed, got: %v", err)
+	}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+	st, _ := status.FromError(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got: %v", err)
+	}
+
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	if retryInfo == nil || retryInfo.RetryDelay.AsDuration() <= 0 {
+		t.Errorf("Expected RetryInfo detail, got: %v", st.Details())
+	}
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	hash := sha256Hex("known-key")
+	cases := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"clients", "partner=" + hash + "; mobile = " + sha256Hex("other-key"), false},
+		{"missing hash", "partner", true},
+		{"missing client", "=" + hash, true},
+		{"plain key instead of hash", "partner=known-key", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseAPIKeys(tc.spec); (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	keys, _ := ParseAPIKeys("partner=" + hash)
+	if client, ok := keys.VerifyAPIKey(context.Background(), "known-key"); !ok || client != "partner" {
+		t.Errorf("Expected partner, got: %q, %v", client, ok)
+	}
+	if _, ok := keys.VerifyAPIKey(context.Background(), hash); ok {
+		t.Error("Expected hash itself not to be a valid key")
+	}
+}
+
+func TestGRPCKeyByAPIKey(t *testing.T) {
+	keys, _ := ParseAPIKeys("partner=" + sha256Hex("known-key"))
+	keyOf := GRPCKeyByAPIKey("x-api-key", keys)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "known-key"))
+	if key := keyOf(ctx); key != "api_key:partner" {
+		t.Errorf("Expected key of partner, got: %s", key)
+	}
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "made-up"))
+	if key := keyOf(ctx); key != "ip:unknown" {
+		t.Errorf("Expected unknown key to fall back to IP, got: %s", key)
+	}
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDynamicPolicy(t *testing.T) {
+	signup := Rule{Algorithm: AlgorithmSlidingWindow, Limit: 5, Window: time.Minute}
+	policy := NewDynamicPolicy(Policy{"POST /signup": signup})
+
+	if rule, ok := policy.RuleOf("POST /signup"); !ok || rule != signup {
+		t.Fatalf("Expected signup rule, got: %v %v", rule, ok)
+	}
+
+	policy.Store(Policy{})
+	if rule, ok := policy.RuleOf("POST /signup"); ok {
+		t.Errorf("Expected no rule after the policy is replaced, got: %v", rule)
+	}
+}
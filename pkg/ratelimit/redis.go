@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
+)
+
+// rateLimitKeyFormat is <prefix>:ratelimit:<algorithm>:<key>, algorithm is part of the key
+// because both algorithms store different data type
+const rateLimitKeyFormat = "%s:ratelimit:%s:%s"
+
+// slidingWindowScript keeps timestamp of every allowed request in a sorted set.
+// ARGV is now in ms, window in ms, limit and unique member of the request.
+// Returns allowed flag, remaining requests and ms until the oldest request leaves the window.
+var slidingWindowScript = goRedis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = tonumber(oldest[2]) + window - now
+return {allowed, limit - count, reset}
+`)
+
+// tokenBucketScript stores remaining tokens and last refill time in a hash, bucket is
+// refilled continuously with limit tokens every window. ARGV is now in ms, window in ms
+// and limit. Returns allowed flag, remaining tokens, ms until the bucket is full and
+// ms until the next token.
+var tokenBucketScript = goRedis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local rate = limit / window
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'refilled_at')
+local tokens = tonumber(state[1]) or limit
+local refilledAt = tonumber(state[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - refilledAt) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'refilled_at', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {allowed, math.floor(tokens), math.ceil((limit - tokens) / rate), retry}
+`)
+
+var _ ILimiter = (*redisLimiter)(nil)
+
+// redisLimiter shares the limit between replicas, each limit is a single key updated
+// atomically by Lua script so it works on Redis Cluster too
+type redisLimiter struct {
+	prefix string
+	client goRedis.UniversalClient
+	now    func() time.Time
+}
+
+// NewRedisLimiter returns limiter backed by Redis, prefix is used to namespace the keys
+// (e.g. application name). Time is taken from the replica clock, so the clocks are
+// expected to be synchronized.
+func NewRedisLimiter(client goRedis.UniversalClient, prefix string) ILimiter {
+	return &redisLimiter{
+		prefix: prefix,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	now := l.now().UnixMilli()
+	window := rule.Window.Milliseconds()
+	keys := []string{fmt.Sprintf(rateLimitKeyFormat, l.prefix, rule.Algorithm, key)}
+
+	switch rule.Algorithm {
+	case AlgorithmTokenBucket:
+		res, err := tokenBucketScript.Run(ctx, l.client, keys, now, window, rule.Limit).Int64Slice()
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to run token bucket: %w", err)
+		}
+		return Result{
+			Allowed:    res[0] == 1,
+			Remaining:  int(res[1]),
+			ResetAfter: time.Duration(res[2]) * time.Millisecond,
+			RetryAfter: time.Duration(res[3]) * time.Millisecond,
+		}, nil
+	default:
+		res, err := slidingWindowScript.Run(ctx, l.client, keys, now, window, rule.Limit, xid.New().String()).Int64Slice()
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to run sliding window: %w", err)
+		}
+		result := Result{
+			Allowed:    res[0] == 1,
+			Remaining:  int(res[1]),
+			ResetAfter: time.Duration(res[2]) * time.Millisecond,
+		}
+		if !result.Allowed {
+			result.RetryAfter = result.ResetAfter
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,9 @@
+package rbac
+
+type RBACConfig struct {
+	// RBACDefaultRole is the role assigned to self sign-up user
+	RBACDefaultRole string `mapstructure:"RBAC_DEFAULT_ROLE"`
+	// RBACPolicy maps role to permissions with format "role=perm,perm;role=perm",
+	// use "*" as permission to grant every permission to a role.
+	RBACPolicy string `mapstructure:"RBAC_POLICY"`
+}
@@ -0,0 +1,19 @@
+package rbac
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// RequirePermission rejects request when the authenticated subject does not own every permission,
+// it must be placed after auth.GinMiddleware.
+func RequirePermission(registry *Registry, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := registry.Authorize(c.Request.Context(), permissions...); err != nil {
+			c.AbortWithStatusJSON(errs.ToREST(err))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	"google.golang.org/grpc"
+)
+
+// MethodPolicies maps gRPC full method name to the required permissions,
+// method without policy is allowed for every caller.
+type MethodPolicies map[string][]string
+
+// UnaryServerInterceptor enforces method level policies, it must be chained after auth interceptor.
+func UnaryServerInterceptor(registry *Registry, policies MethodPolicies) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorizeGRPC(ctx, registry, policies, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the stream version of UnaryServerInterceptor
+func StreamServerInterceptor(registry *Registry, policies MethodPolicies) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorizeGRPC(ss.Context(), registry, policies, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func authorizeGRPC(ctx context.Context, registry *Registry, policies MethodPolicies, method string) error {
+	permissions, ok := policies[method]
+	if !ok {
+		return nil
+	}
+
+	if err := registry.Authorize(ctx, permissions...); err != nil {
+		return errs.GRPCError(err)
+	}
+
+	return nil
+}
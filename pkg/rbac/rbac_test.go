@@ -0,0 +1,144 @@
+package rbac_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/rbac"
+)
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+
+	registry, err := NewRegistry(&RBACConfig{
+		RBACDefaultRole: "member",
+		RBACPolicy:      "admin=*; member=users:read ; auditor=users:read,audit:read",
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry returned unexpected error: %v", err)
+	}
+	return registry
+}
+
+func TestNewRegistry(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     RBACConfig
+		wantErr bool
+	}{
+		{"valid policy", RBACConfig{RBACDefaultRole: "member", RBACPolicy: "member=users:read"}, false},
+		{"empty policy", RBACConfig{RBACDefaultRole: "member"}, true},
+		{"missing default role", RBACConfig{RBACPolicy: "member=users:read"}, true},
+		{"default role not in policy", RBACConfig{RBACDefaultRole: "guest", RBACPolicy: "member=users:read"}, true},
+		{"rule without role", RBACConfig{RBACDefaultRole: "member", RBACPolicy: "member=users:read;=users:write"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRegistry(&tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRegistryPermission(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	if got := registry.Roles(); len(got) != 3 || got[0] != "admin" {
+		t.Errorf("Unexpected roles: %v", got)
+	}
+
+	cases := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{"admin", "anything:goes", true},
+		{"member", "users:read", true},
+		{"member", "users:write", false},
+		{"auditor", "audit:read", true},
+		{"unknown", "users:read", false},
+	}
+
+	for _, tc := range cases {
+		if got := registry.HasPermission(tc.role, tc.permission); got != tc.want {
+			t.Errorf("HasPermission(%s, %s) expected %v, got: %v", tc.role, tc.permission, tc.want, got)
+		}
+	}
+
+	memberCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: "u1", Role: "member"})
+	if err := registry.Authorize(context.Background(), "users:read"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated, got: %v", err)
+	}
+	if err := registry.Authorize(memberCtx, "users:read", "users:write"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got: %v", err)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := newTestRegistry(t)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if role := c.GetHeader("X-Test-Role"); role != "" {
+			ctx := auth.WithSubject(c.Request.Context(), auth.Subject{UniqueId: "u1", Role: role})
+			c.Request = c.Request.WithContext(ctx)
+		}
+	})
+	router.DELETE("/users", RequirePermission(registry, "users:delete"), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	cases := map[string]int{
+		"":       http.StatusUnauthorized,
+		"member": http.StatusForbidden,
+		"admin":  http.StatusNoContent,
+	}
+
+	for role, wantStatus := range cases {
+		req := httptest.NewRequest(http.MethodDelete, "/users", nil)
+		req.Header.Set("X-Test-Role", role)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != wantStatus {
+			t.Errorf("Role %q expected status %d, got: %d", role, wantStatus, rec.Code)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	registry := newTestRegistry(t)
+	interceptor := UnaryServerInterceptor(registry, MethodPolicies{
+		"/test.Service/Delete": {"users:delete"},
+	})
+	handler := func(ctx context.Context, req any) (any, error) { return nil, nil }
+	memberCtx := auth.WithSubject(context.Background(), auth.Subject{UniqueId: "u1", Role: "member"})
+
+	_, err := interceptor(memberCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Delete"}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got: %v", err)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Delete"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got: %v", err)
+	}
+
+	_, err = interceptor(memberCtx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, handler)
+	if err != nil {
+		t.Errorf("Expected method without policy to pass, got: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/auth"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+)
+
+// PermissionAll grants every permission to the role
+const PermissionAll = "*"
+
+var (
+	ErrUnauthenticated = errs.Unauthorized("request is not authenticated")
+	ErrForbidden       = errs.Forbidden("permission denied")
+)
+
+type Registry struct {
+	defaultRole string
+	roles       map[string]map[string]struct{}
+}
+
+// NewRegistry parse role to permission mapping from config, the default role must be part of the policy.
+func NewRegistry(cfg *RBACConfig) (*Registry, error) {
+	roles, err := parsePolicy(cfg.RBACPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.RBACDefaultRole == "" {
+		return nil, errors.New("rbac: default role is required")
+	}
+
+	if _, ok := roles[cfg.RBACDefaultRole]; !ok {
+		return nil, fmt.Errorf("rbac: default role %q is not defined in policy", cfg.RBACDefaultRole)
+	}
+
+	return &Registry{
+		defaultRole: cfg.RBACDefaultRole,
+		roles:       roles,
+	}, nil
+}
+
+func parsePolicy(policy string) (map[string]map[string]struct{}, error) {
+	roles := make(map[string]map[string]struct{})
+	for _, rule := range strings.Split(policy, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		role, perms, found := strings.Cut(rule, "=")
+		role = strings.TrimSpace(role)
+		if !found || role == "" {
+			return nil, fmt.Errorf("rbac: invalid policy rule %q", rule)
+		}
+
+		permissions := make(map[string]struct{})
+		for _, perm := range strings.Split(perms, ",") {
+			if perm = strings.TrimSpace(perm); perm != "" {
+				permissions[perm] = struct{}{}
+			}
+		}
+		roles[role] = permissions
+	}
+
+	if len(roles) == 0 {
+		return nil, errors.New("rbac: policy has no role")
+	}
+
+	return roles, nil
+}
+
+func (r *Registry) DefaultRole() string {
+	return r.defaultRole
+}
+
+func (r *Registry) HasRole(role string) bool {
+	_, ok := r.roles[role]
+	return ok
+}
+
+// Roles returns every registered role in sorted order
+func (r *Registry) Roles() []string {
+	roles := make([]string, 0, len(r.roles))
+	for role := range r.roles {
+		roles = append(roles, role)
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+func (r *Registry) HasPermission(role, permission string) bool {
+	permissions, ok := r.roles[role]
+	if !ok {
+		return false
+	}
+
+	if _, ok := permissions[PermissionAll]; ok {
+		return true
+	}
+
+	_, ok = permissions[permission]
+	return ok
+}
+
+// Authorize checks the authenticated subject on ctx own every given permission
+func (r *Registry) Authorize(ctx context.Context, permissions ...string) error {
+	subject, ok := auth.SubjectFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	for _, permission := range permissions {
+		if !r.HasPermission(subject.Role, permission) {
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
@@ -6,18 +6,24 @@ import (
 
 type RedisConfig struct {
 	// Redis Configuration
-	RedisDB           int      `mapstructure:"REDIS_DB"`
-	RedisAddr         string   `mapstructure:"REDIS_ADDR"`
+	RedisDB           int      `mapstructure:"REDIS_DB" validate:"min=0,max=15"`
+	RedisAddr         string   `mapstructure:"REDIS_ADDR" validate:"required_without_all=RedisClusterAddrs RedisSentinelAddrs,omitempty,hostname_port"`
 	RedisUsername     string   `mapstructure:"REDIS_USERNAME"`
 	RedisPassword     string   `mapstructure:"REDIS_PASSWORD"`
 	RedisIsCluster    bool     `mapstructure:"REDIS_IS_CLUSTER"`
-	RedisClusterAddrs []string `mapstructure:"REDIS_CLUSTER_ADDRS"`
+	RedisClusterAddrs []string `mapstructure:"REDIS_CLUSTER_ADDRS" validate:"omitempty,dive,hostname_port"`
+
+	// Sentinel mode is used when master name is set
+	RedisMasterName       string   `mapstructure:"REDIS_MASTER_NAME"`
+	RedisSentinelAddrs    []string `mapstructure:"REDIS_SENTINEL_ADDRS" validate:"omitempty,dive,hostname_port"`
+	RedisSentinelUsername string   `mapstructure:"REDIS_SENTINEL_USERNAME"`
+	RedisSentinelPassword string   `mapstructure:"REDIS_SENTINEL_PASSWORD"`
 
 	// Connection pool settings
-	RedisPoolSize        int           `mapstructure:"REDIS_POOL_SIZE"`
-	RedisMinIdleConns    int           `mapstructure:"REDIS_MIN_IDLE_CONNS"`
+	RedisPoolSize        int           `mapstructure:"REDIS_POOL_SIZE" validate:"min=0"`
+	RedisMinIdleConns    int           `mapstructure:"REDIS_MIN_IDLE_CONNS" validate:"min=0"`
 	RedisPoolTimeout     time.Duration `mapstructure:"REDIS_POOL_TIMEOUT"`
-	RedisMaxRetries      int           `mapstructure:"REDIS_MAX_RETRIES"`
+	RedisMaxRetries      int           `mapstructure:"REDIS_MAX_RETRIES" validate:"min=-1"` // -1 disables retry
 	RedisMinRetryBackoff time.Duration `mapstructure:"REDIS_MIN_RETRY_BACKOFF"`
 	RedisMaxRetryBackoff time.Duration `mapstructure:"REDIS_MAX_RETRY_BACKOFF"`
 
@@ -31,9 +37,30 @@ type RedisConfig struct {
 	RedisEnableTLS             bool `mapstructure:"REDIS_ENABLE_TLS"`
 	RedisTLSInsecureSkipVerify bool `mapstructure:"REDIS_TLS_INSECURE_SKIP_VERIFY"`
 
-	// Cluster specific configurations
+	// Cluster specific configurations, routing options also apply to sentinel mode
+	// where read only command is sent to replica
 	RedisMaxRedirects   int  `mapstructure:"REDIS_MAX_REDIRECTS"`
 	RedisRouteByLatency bool `mapstructure:"REDIS_ROUTE_BY_LATENCY"`
 	RedisRouteRandomly  bool `mapstructure:"REDIS_ROUTE_RANDOMLY"`
 	RedisReadOnly       bool `mapstructure:"REDIS_READ_ONLY"`
 }
+
+// Connection mode of Redis client
+const (
+	ModeStandalone = "standalone"
+	ModeCluster    = "cluster"
+	ModeSentinel   = "sentinel"
+)
+
+// Mode returns sentinel when master name is set, cluster when cluster flag is set and
+// standalone otherwise
+func (c *RedisConfig) Mode() string {
+	switch {
+	case c.RedisMasterName != "":
+		return ModeSentinel
+	case c.RedisIsCluster:
+		return ModeCluster
+	default:
+		return ModeStandalone
+	}
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"time"
 
 	goRedis "github.com/redis/go-redis/v9"
 )
 
+// defaultDialTimeout bounds the test connection when dial timeout is not configured
+const defaultDialTimeout = 5 * time.Second
+
 type redisClient struct {
 	cfg *RedisConfig
 }
 
-func NewClient(cfg *RedisConfig) (*goRedis.Client, error) {
+// NewClient returns standalone, cluster or sentinel client depending on RedisConfig.Mode,
+// the connection is tested before it is returned
+func NewClient(cfg *RedisConfig) (goRedis.UniversalClient, error) {
 	newCli := redisClient{
 		cfg: cfg,
 	}
@@ -25,82 +31,64 @@ func NewClient(cfg *RedisConfig) (*goRedis.Client, error) {
 	return client, nil
 }
 
-func (r *redisClient) connect() (*goRedis.Client, error) {
-	if !r.cfg.RedisIsCluster {
-		return r.connectRedisStandaloneClient()
-	}
-	return r.connectRedisStandaloneClient()
-}
-
-// BuildRedisStandaloneClient creates a standalone Redis client
-func (r *redisClient) connectRedisStandaloneClient() (*goRedis.Client, error) {
-	options := &goRedis.Options{
-		Addr:     r.cfg.RedisAddr,
-		Password: r.cfg.RedisPassword,
-		DB:       r.cfg.RedisDB,
-
-		PoolSize:        r.cfg.RedisPoolSize,
-		MinIdleConns:    r.cfg.RedisMinIdleConns,
-		PoolTimeout:     r.cfg.RedisPoolTimeout,
-		MaxRetries:      r.cfg.RedisMaxRetries,
-		MinRetryBackoff: r.cfg.RedisMinRetryBackoff,
-		MaxRetryBackoff: r.cfg.RedisMaxRetryBackoff,
-
-		DialTimeout:     r.cfg.RedisDialTimeout,
-		ReadTimeout:     r.cfg.RedisReadTimeout,
-		WriteTimeout:    r.cfg.RedisWriteTimeout,
-		ConnMaxIdleTime: r.cfg.RedisIdleTimeout,
-		OnConnect: func(ctx context.Context, cn *goRedis.Conn) error {
-			_, err := cn.Ping(ctx).Result()
-			return err
-		},
+func (r *redisClient) connect() (goRedis.UniversalClient, error) {
+	options, err := r.universalOptions()
+	if err != nil {
+		return nil, err
 	}
 
-	if r.cfg.RedisEnableTLS {
-		options.TLSConfig = &tls.Config{
-			MinVersion:         tls.VersionTLS12,
-			InsecureSkipVerify: r.cfg.RedisTLSInsecureSkipVerify,
+	var client goRedis.UniversalClient
+	switch r.cfg.Mode() {
+	case ModeSentinel:
+		if r.cfg.RedisRouteByLatency || r.cfg.RedisRouteRandomly {
+			client = goRedis.NewFailoverClusterClient(options.Failover())
+		} else {
+			client = goRedis.NewFailoverClient(options.Failover())
 		}
+	case ModeCluster:
+		client = goRedis.NewClusterClient(options.Cluster())
+	default:
+		client = goRedis.NewClient(options.Simple())
 	}
 
-	client := goRedis.NewClient(options)
 	// Perform a test connection
-	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.RedisDialTimeout)
+	dialTimeout := r.cfg.RedisDialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
 		client.Close()
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		return nil, fmt.Errorf("failed to connect to Redis %s: %w", r.cfg.Mode(), err)
 	}
 
 	return client, nil
 }
 
-func (c *RedisConfig) connectRedisClusterClient() (*goRedis.ClusterClient, error) {
-	if len(c.RedisClusterAddrs) == 0 {
-		return nil, fmt.Errorf("no cluster addresses provided for Redis cluster")
-	}
-
-	options := &goRedis.ClusterOptions{
-		Addrs:    c.RedisClusterAddrs,
-		Password: c.RedisPassword,
+// universalOptions maps config to options shared by every mode, addresses depend on the mode
+func (r *redisClient) universalOptions() (*goRedis.UniversalOptions, error) {
+	options := &goRedis.UniversalOptions{
+		Username: r.cfg.RedisUsername,
+		Password: r.cfg.RedisPassword,
 
-		PoolSize:        c.RedisPoolSize,
-		MinIdleConns:    c.RedisMinIdleConns,
-		PoolTimeout:     c.RedisPoolTimeout,
-		MaxRetries:      c.RedisMaxRetries,
-		MinRetryBackoff: c.RedisMinRetryBackoff,
-		MaxRetryBackoff: c.RedisMaxRetryBackoff,
+		PoolSize:        r.cfg.RedisPoolSize,
+		MinIdleConns:    r.cfg.RedisMinIdleConns,
+		PoolTimeout:     r.cfg.RedisPoolTimeout,
+		MaxRetries:      r.cfg.RedisMaxRetries,
+		MinRetryBackoff: r.cfg.RedisMinRetryBackoff,
+		MaxRetryBackoff: r.cfg.RedisMaxRetryBackoff,
 
-		DialTimeout:     c.RedisDialTimeout,
-		ReadTimeout:     c.RedisReadTimeout,
-		WriteTimeout:    c.RedisWriteTimeout,
-		ConnMaxIdleTime: c.RedisIdleTimeout,
+		DialTimeout:     r.cfg.RedisDialTimeout,
+		ReadTimeout:     r.cfg.RedisReadTimeout,
+		WriteTimeout:    r.cfg.RedisWriteTimeout,
+		ConnMaxIdleTime: r.cfg.RedisIdleTimeout,
 
-		MaxRedirects:   c.RedisMaxRedirects,
-		RouteByLatency: c.RedisRouteByLatency,
-		RouteRandomly:  c.RedisRouteRandomly,
-		ReadOnly:       c.RedisReadOnly,
+		MaxRedirects:   r.cfg.RedisMaxRedirects,
+		RouteByLatency: r.cfg.RedisRouteByLatency,
+		RouteRandomly:  r.cfg.RedisRouteRandomly,
+		ReadOnly:       r.cfg.RedisReadOnly,
 
 		OnConnect: func(ctx context.Context, cn *goRedis.Conn) error {
 			_, err := cn.Ping(ctx).Result()
@@ -108,22 +96,36 @@ func (c *RedisConfig) connectRedisClusterClient() (*goRedis.ClusterClient, error
 		},
 	}
 
-	if c.RedisEnableTLS {
-		options.TLSConfig = &tls.Config{
-			MinVersion:         tls.VersionTLS12,
-			InsecureSkipVerify: c.RedisTLSInsecureSkipVerify,
+	switch r.cfg.Mode() {
+	case ModeSentinel:
+		if len(r.cfg.RedisSentinelAddrs) == 0 {
+			return nil, fmt.Errorf("no sentinel addresses provided for Redis master %s", r.cfg.RedisMasterName)
+		}
+		options.Addrs = r.cfg.RedisSentinelAddrs
+		options.MasterName = r.cfg.RedisMasterName
+		options.SentinelUsername = r.cfg.RedisSentinelUsername
+		options.SentinelPassword = r.cfg.RedisSentinelPassword
+		options.DB = r.cfg.RedisDB
+	case ModeCluster:
+		// Cluster has no database other than 0
+		options.Addrs = r.cfg.RedisClusterAddrs
+		if len(options.Addrs) == 0 && r.cfg.RedisAddr != "" {
+			options.Addrs = []string{r.cfg.RedisAddr}
 		}
+		if len(options.Addrs) == 0 {
+			return nil, fmt.Errorf("no cluster addresses provided for Redis cluster")
+		}
+	default:
+		options.Addrs = []string{r.cfg.RedisAddr}
+		options.DB = r.cfg.RedisDB
 	}
 
-	client := goRedis.NewClusterClient(options)
-	// Perform a test connection
-	ctx, cancel := context.WithTimeout(context.Background(), c.RedisDialTimeout)
-	defer cancel()
-
-	if err := client.Ping(ctx).Err(); err != nil {
-		client.Close()
-		return nil, fmt.Errorf("failed to connect to Redis cluster: %w", err)
+	if r.cfg.RedisEnableTLS {
+		options.TLSConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: r.cfg.RedisTLSInsecureSkipVerify,
+		}
 	}
 
-	return client, nil
+	return options, nil
 }
@@ -0,0 +1,99 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/redis"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis/redistest"
+)
+
+func TestMode(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  RedisConfig
+		mode string
+	}{
+		{"standalone by default", RedisConfig{RedisAddr: "localhost:6379"}, ModeStandalone},
+		{"cluster flag", RedisConfig{RedisIsCluster: true}, ModeCluster},
+		{"master name", RedisConfig{RedisMasterName: "mymaster", RedisIsCluster: true}, ModeSentinel},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if mode := tc.cfg.Mode(); mode != tc.mode {
+				t.Errorf("Expected mode %s, got: %s", tc.mode, mode)
+			}
+		})
+	}
+}
+
+func TestNewClientStandalone(t *testing.T) {
+	server, cfg := redistest.Standalone(t)
+	cfg.RedisDB = 2
+
+	client, err := NewClient(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, ok := client.(*goRedis.Client); !ok {
+		t.Errorf("Expected standalone client, got: %T", client)
+	}
+
+	if err := client.Set(context.Background(), "key", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	server.Select(2)
+	if value, _ := server.Get("key"); value != "value" {
+		t.Errorf("Expected key to be written to the configured DB, got: %q", value)
+	}
+}
+
+func TestNewClientCluster(t *testing.T) {
+	cfg := redistest.Cluster(t, 3)
+
+	client, err := NewClient(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, ok := client.(*goRedis.ClusterClient); !ok {
+		t.Errorf("Expected cluster client, got: %T", client)
+	}
+
+	// Multi key command must stay in one slot by sharing the hash tag
+	ctx := context.Background()
+	if err := client.MSet(ctx, "{user}:a", "1", "{user}:b", "2").Err(); err != nil {
+		t.Fatal(err)
+	}
+	values, err := client.MGet(ctx, "{user}:a", "{user}:b").Result()
+	if err != nil || values[0] != "1" || values[1] != "2" {
+		t.Errorf("Expected both values, got: %v, %v", values, err)
+	}
+}
+
+func TestNewClientInvalidConfig(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  RedisConfig
+	}{
+		{"sentinel without sentinel addresses", RedisConfig{RedisMasterName: "mymaster"}},
+		{"cluster without addresses", RedisConfig{RedisIsCluster: true}},
+		{"unreachable server", RedisConfig{RedisAddr: "127.0.0.1:1", RedisDialTimeout: 100 * time.Millisecond}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if client, err := NewClient(&tc.cfg); err == nil {
+				client.Close()
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/graceful"
+)
+
+const defaultElectionTTL = 15 * time.Second
+
+type ElectionConfig struct {
+	// Name of the election, replicas with the same name elect a single leader
+	Name string
+	// TTL of the leadership lease, it bounds how long the leadership stays vacant when
+	// the leader crashes. Default is 15 seconds.
+	TTL time.Duration
+	// RetryInterval is how often follower tries to become the leader, default is a third of TTL
+	RetryInterval time.Duration
+}
+
+type lockCtxKey struct{}
+
+// LockFromContext returns leadership lock of ctx given to the leader callback, its token
+// fences writes of the previous leader
+func LockFromContext(ctx context.Context) (*Lock, bool) {
+	lock, ok := ctx.Value(lockCtxKey{}).(*Lock)
+	return lock, ok
+}
+
+// RunAsLeader campaigns for leadership until ctx is cancelled and runs fn while this
+// replica is the leader. ctx given to fn is cancelled when the leadership is lost, fn
+// returning nil gives up the leadership and campaigns again, while error is returned.
+func RunAsLeader(ctx context.Context, locker *Locker, cfg ElectionConfig, fn func(ctx context.Context) error) error {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultElectionTTL
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = cfg.TTL / 3
+	}
+
+	ticker := time.NewTicker(cfg.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := lead(ctx, locker, cfg, fn); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs fn when leadership is acquired, not being the leader is not an error
+func lead(ctx context.Context, locker *Locker, cfg ElectionConfig, fn func(ctx context.Context) error) error {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lock, err := locker.TryAcquire(leaderCtx, cfg.Name, cfg.TTL)
+	if err != nil {
+		if !errors.Is(err, ErrLockNotAcquired) {
+			slog.WarnContext(ctx, "[REDIS] failed to campaign for leadership", "election", cfg.Name, "error", err)
+		}
+		return nil
+	}
+	defer lock.Release(context.WithoutCancel(ctx))
+
+	slog.InfoContext(ctx, "[REDIS] leadership acquired", "election", cfg.Name, "token", lock.Token())
+	go func() {
+		<-lock.Done()
+		cancel()
+	}()
+
+	err = fn(context.WithValue(leaderCtx, lockCtxKey{}, lock))
+	if errors.Is(lock.Err(), ErrLockLost) {
+		slog.WarnContext(ctx, "[REDIS] leadership lost", "election", cfg.Name, "token", lock.Token())
+		return nil
+	}
+	return err
+}
+
+// LeaderComponent returns graceful component that runs fn only on the elected replica,
+// the component is named cfg.Name
+func LeaderComponent(locker *Locker, cfg ElectionConfig, fn func(ctx context.Context) error) graceful.Component {
+	return graceful.Component{
+		Name: cfg.Name,
+		Run: func(ctx context.Context) error {
+			return RunAsLeader(ctx, locker, cfg, fn)
+		},
+	}
+}
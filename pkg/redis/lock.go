@@ -0,0 +1,216 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
+)
+
+// defaultLockRetryInterval is the polling interval of Acquire
+const defaultLockRetryInterval = 100 * time.Millisecond
+
+// Lock and fencing counter share the {name} hash tag, so scripts below stay in a single
+// slot on Redis Cluster. Fencing counter never expires, so token keeps increasing.
+const (
+	lockKeyFormat        = "%s:lock:{%s}"
+	lockFencingKeyFormat = "%s:lock:{%s}:fencing"
+)
+
+var (
+	// ErrLockNotAcquired is returned by TryAcquire when the lock is held by other owner
+	ErrLockNotAcquired = errors.New("redis: lock is not acquired")
+	// ErrLockLost is the Err of lock which lease could not be extended before it expired
+	ErrLockLost = errors.New("redis: lock is lost")
+)
+
+// acquireLockScript sets owner ARGV[1] for ARGV[2] ms and returns the next fencing token
+var acquireLockScript = goRedis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return false
+`)
+
+// extendLockScript extends the lease to ARGV[2] ms when the lock is still owned by ARGV[1]
+var extendLockScript = goRedis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript deletes the lock when it is still owned by ARGV[1]
+var releaseLockScript = goRedis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Locker creates distributed locks on a single Redis deployment. It is not Redlock, the
+// lock may be held twice during failover of Redis, so writes guarded by the lock should
+// be rejected by the storage when they carry older fencing token.
+type Locker struct {
+	prefix string
+	client goRedis.UniversalClient
+}
+
+// NewLocker returns locker which keys are namespaced by prefix (e.g. application name)
+func NewLocker(client goRedis.UniversalClient, prefix string) *Locker {
+	return &Locker{
+		prefix: prefix,
+		client: client,
+	}
+}
+
+// TryAcquire takes lock of name once, ErrLockNotAcquired is returned when it is held.
+// The lease of ttl is extended in background until the lock is released, ctx is
+// cancelled or the lease cannot be extended, see Lock.Done.
+func (l *Locker) TryAcquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	lock := &Lock{
+		locker: l,
+		name:   name,
+		owner:  xid.New().String(),
+		ttl:    ttl,
+		done:   make(chan struct{}),
+	}
+
+	keys := []string{fmt.Sprintf(lockKeyFormat, l.prefix, name), fmt.Sprintf(lockFencingKeyFormat, l.prefix, name)}
+	acquiredAt := time.Now()
+	token, err := acquireLockScript.Run(ctx, l.client, keys, lock.owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		if errors.Is(err, goRedis.Nil) {
+			return nil, ErrLockNotAcquired
+		}
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	lock.token = token
+	go lock.keepAlive(ctx, acquiredAt.Add(ttl))
+	return lock, nil
+}
+
+// Acquire waits until lock of name is taken or ctx is done, see TryAcquire
+func (l *Locker) Acquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	ticker := time.NewTicker(defaultLockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		lock, err := l.TryAcquire(ctx, name, ttl)
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Lock is a held distributed lock
+type Lock struct {
+	locker *Locker
+	name   string
+	owner  string
+	token  int64
+	ttl    time.Duration
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// Token returns fencing token of the lock, every acquisition of the same name gets greater token
+func (lock *Lock) Token() int64 {
+	return lock.token
+}
+
+// Done is closed when the lock is released, lost or its ctx is cancelled
+func (lock *Lock) Done() <-chan struct{} {
+	return lock.done
+}
+
+// Err returns why Done is closed, it is nil while the lock is held or after it is released,
+// ErrLockLost when the lease has expired and ctx error when ctx is cancelled
+func (lock *Lock) Err() error {
+	select {
+	case <-lock.done:
+		return lock.err
+	default:
+		return nil
+	}
+}
+
+// Release gives up the lock, releasing lock that has ended is no-op
+func (lock *Lock) Release(ctx context.Context) error {
+	select {
+	case <-lock.done:
+		return nil
+	default:
+	}
+
+	lock.finish(nil)
+	return lock.release(ctx)
+}
+
+func (lock *Lock) release(ctx context.Context) error {
+	key := fmt.Sprintf(lockKeyFormat, lock.locker.prefix, lock.name)
+	if err := releaseLockScript.Run(ctx, lock.locker.client, []string{key}, lock.owner).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", lock.name, err)
+	}
+	return nil
+}
+
+func (lock *Lock) finish(err error) {
+	lock.once.Do(func() {
+		lock.err = err
+		close(lock.done)
+	})
+}
+
+// keepAlive extends the lease every third of ttl. Failed extension is retried while the
+// next retry still lands before the lease expires, so short Redis outage does not lose the
+// lock and Done is closed before other owner is able to take it. expireAt is measured
+// before the request, so it is never later than the expiry of the key.
+func (lock *Lock) keepAlive(ctx context.Context, expireAt time.Time) {
+	interval := lock.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	key := fmt.Sprintf(lockKeyFormat, lock.locker.prefix, lock.name)
+	for {
+		select {
+		case <-lock.done:
+			return
+		case <-ctx.Done():
+			lock.finish(ctx.Err())
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lock.ttl)
+			lock.release(releaseCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			extendedAt := time.Now()
+			// Request is bounded by half of the interval, so failed request and the next retry
+			// both end before the lease expires even when the client retries are slow
+			extendCtx, cancel := context.WithTimeout(ctx, interval/2)
+			extended, err := extendLockScript.Run(extendCtx, lock.locker.client, []string{key}, lock.owner, lock.ttl.Milliseconds()).Int64()
+			cancel()
+			switch {
+			case err == nil && extended == 1:
+				expireAt = extendedAt.Add(lock.ttl)
+			case ctx.Err() != nil:
+				// Cancelled ctx releases the lock on the next iteration
+			case err == nil || time.Now().Add(interval).After(expireAt):
+				lock.finish(ErrLockLost)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goRedis "github.com/redis/go-redis/v9"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/redis"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis/redistest"
+)
+
+func newLocker(t *testing.T) (*miniredis.Miniredis, *Locker) {
+	t.Helper()
+
+	server, cfg := redistest.Standalone(t)
+	client, err := NewClient(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return server, NewLocker(client, "test")
+}
+
+// lockBackend is locker of a redistest backend with the client to inspect its keys
+type lockBackend struct {
+	client goRedis.UniversalClient
+	locker *Locker
+	prefix string
+}
+
+func newLockBackends(t *testing.T) map[string]lockBackend {
+	t.Helper()
+
+	backends := make(map[string]lockBackend)
+	for name, cfg := range redistest.Backends(t) {
+		client, err := NewClient(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.Close() })
+
+		prefix := redistest.Prefix()
+		backends[name] = lockBackend{client: client, locker: NewLocker(client, prefix), prefix: prefix}
+	}
+	return backends
+}
+
+// waitDone fails the test when ch is not closed in time
+func waitDone(t *testing.T, ch <-chan struct{}, message string) {
+	t.Helper()
+
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal(message)
+	}
+}
+
+func TestLockFencingToken(t *testing.T) {
+	ctx := context.Background()
+	for name, backend := range newLockBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			first, err := backend.locker.TryAcquire(ctx, "job", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := backend.locker.TryAcquire(ctx, "job", time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+				t.Fatalf("Expected held lock not to be acquired, got: %v", err)
+			}
+			if err := first.Release(ctx); err != nil {
+				t.Fatal(err)
+			}
+			waitDone(t, first.Done(), "Expected released lock to be done")
+
+			second, err := backend.locker.Acquire(ctx, "job", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer second.Release(ctx)
+			if second.Token() <= first.Token() {
+				t.Errorf("Expected fencing token to increase, got: %d then %d", first.Token(), second.Token())
+			}
+		})
+	}
+}
+
+func TestLockLost(t *testing.T) {
+	ctx := context.Background()
+	for name, backend := range newLockBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			lock, err := backend.locker.TryAcquire(ctx, "job", 150*time.Millisecond)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Lease is extended in background until other owner takes the lock over
+			time.Sleep(200 * time.Millisecond)
+			if lock.Err() != nil {
+				t.Fatalf("Expected lease to be extended, got: %v", lock.Err())
+			}
+			backend.client.Set(ctx, backend.prefix+":lock:{job}", "other-owner", time.Minute)
+
+			waitDone(t, lock.Done(), "Expected lock to be lost")
+			if !errors.Is(lock.Err(), ErrLockLost) {
+				t.Errorf("Expected ErrLockLost, got: %v", lock.Err())
+			}
+		})
+	}
+}
+
+func TestLockLostOnRedisOutage(t *testing.T) {
+	server, locker := newLocker(t)
+
+	ttl := 300 * time.Millisecond
+	acquiredAt := time.Now()
+	lock, err := locker.TryAcquire(context.Background(), "job", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	waitDone(t, lock.Done(), "Expected lock to be lost")
+	if !errors.Is(lock.Err(), ErrLockLost) {
+		t.Errorf("Expected ErrLockLost, got: %v", lock.Err())
+	}
+	// Other owner is able to take the lock once the key expires, so the holder must stop first
+	if lostAfter := time.Since(acquiredAt); lostAfter >= ttl {
+		t.Errorf("Expected lock to be lost before its ttl %v, got: %v", ttl, lostAfter)
+	}
+}
+
+func TestLockReleasedOnCancel(t *testing.T) {
+	for name, backend := range newLockBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			lock, err := backend.locker.TryAcquire(ctx, "job", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cancel()
+			waitDone(t, lock.Done(), "Expected cancelled lock to be done")
+			for i := 0; backend.client.Exists(context.Background(), backend.prefix+":lock:{job}").Val() > 0; i++ {
+				if i > 100 {
+					t.Fatal("Expected lock of cancelled ctx to be released")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestRunAsLeader(t *testing.T) {
+	for name, backend := range newLockBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			locker := backend.locker
+			cfg := ElectionConfig{Name: "relay", TTL: 300 * time.Millisecond, RetryInterval: 20 * time.Millisecond}
+
+			var leaders atomic.Int64
+			elected := make(chan string, 2)
+			campaign := func(replica string) (context.CancelFunc, <-chan error) {
+				ctx, cancel := context.WithCancel(context.Background())
+				stopped := make(chan error, 1)
+				go func() {
+					stopped <- RunAsLeader(ctx, locker, cfg, func(ctx context.Context) error {
+						if leaders.Add(1) > 1 {
+							t.Error("Expected a single leader at a time")
+						}
+						defer leaders.Add(-1)
+
+						if _, ok := LockFromContext(ctx); !ok {
+							t.Error("Expected leader ctx to carry the lock")
+						}
+						elected <- replica
+						<-ctx.Done()
+						return nil
+					})
+				}()
+				return cancel, stopped
+			}
+
+			stopFirst, firstStopped := campaign("first")
+			if leader := <-elected; leader != "first" {
+				t.Fatalf("Expected first replica to lead, got: %s", leader)
+			}
+			stopSecond, secondStopped := campaign("second")
+			defer stopSecond()
+
+			// Follower takes over once the leader stops
+			stopFirst()
+			if err := <-firstStopped; err != nil {
+				t.Errorf("Expected leader to stop without error, got: %v", err)
+			}
+			select {
+			case leader := <-elected:
+				if leader != "second" {
+					t.Errorf("Expected second replica to take over, got: %s", leader)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Expected follower to take over the leadership")
+			}
+
+			stopSecond()
+			if err := <-secondStopped; err != nil {
+				t.Errorf("Expected follower to stop without error, got: %v", err)
+			}
+		})
+	}
+}
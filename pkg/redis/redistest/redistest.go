@@ -0,0 +1,73 @@
+// Package redistest starts local Redis servers for tests
+package redistest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rs/xid"
+	"github.com/wahyurudiyan/go-boilerplate/pkg/redis"
+)
+
+// ClusterAddrsEnv points the tests to a real Redis cluster, the value is comma separated
+// addresses (e.g. 127.0.0.1:7000,127.0.0.1:7001,127.0.0.1:7002). The cluster of
+// deploy/redis-cluster is started by `make redis-cluster-up`.
+const ClusterAddrsEnv = "REDIS_TEST_CLUSTER_ADDRS"
+
+// Standalone starts miniredis, it is stopped when the test ends
+func Standalone(t testing.TB) (*miniredis.Miniredis, redis.RedisConfig) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	return server, redis.RedisConfig{RedisAddr: server.Addr()}
+}
+
+// Cluster returns config of a cluster with the given number of nodes. Real cluster
+// from ClusterAddrsEnv is used when it is set, otherwise miniredis nodes are started.
+//
+// Every miniredis node reports that it owns all slots, so the commands are served by
+// the node that answered CLUSTER SLOTS. It verifies cluster client wiring only, slot
+// routing and cross slot errors need the real cluster, see Backends.
+func Cluster(t testing.TB, nodes int) redis.RedisConfig {
+	t.Helper()
+
+	if cfg, ok := realCluster(); ok {
+		return cfg
+	}
+
+	cfg := redis.RedisConfig{RedisIsCluster: true}
+	for range nodes {
+		cfg.RedisClusterAddrs = append(cfg.RedisClusterAddrs, miniredis.RunT(t).Addr())
+	}
+	return cfg
+}
+
+// Backends returns config of miniredis and, when ClusterAddrsEnv is set, of the real
+// cluster keyed by "cluster". Tests of Lua scripts run on every backend since only the
+// real cluster rejects keys of a script that do not share the same slot.
+func Backends(t testing.TB) map[string]redis.RedisConfig {
+	t.Helper()
+
+	_, cfg := Standalone(t)
+	backends := map[string]redis.RedisConfig{"miniredis": cfg}
+	if cfg, ok := realCluster(); ok {
+		backends["cluster"] = cfg
+	}
+	return backends
+}
+
+// Prefix returns unique key prefix, the real cluster is shared by every test run so
+// each test namespaces its keys
+func Prefix() string {
+	return "test-" + xid.New().String()
+}
+
+func realCluster() (redis.RedisConfig, bool) {
+	addrs := os.Getenv(ClusterAddrsEnv)
+	if addrs == "" {
+		return redis.RedisConfig{}, false
+	}
+	return redis.RedisConfig{RedisIsCluster: true, RedisClusterAddrs: strings.Split(addrs, ",")}, true
+}
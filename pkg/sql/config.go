@@ -4,17 +4,22 @@ import "time"
 
 type SQLConfig struct {
 	// Database Configuration
-	DatabaseName      string `mapstructure:"database_name"`
-	DatabaseHost      string `mapstructure:"database_host"`
-	DatabasePort      string `mapstructure:"database_port"`
-	DatabaseDriver    string `mapstructure:"database_driver"` // e.g., "mysql, postgres, etc"
-	DatabaseCharset   string `mapstructure:"database_charset"`
-	DatabaseUsername  string `mapstructure:"database_username"`
-	DatabasePassword  string `mapstructure:"database_password"`
-	DatabaseParsetime bool   `mapstructure:"database_parsetime"`
+	DatabaseName     string `mapstructure:"database_name" validate:"required"`
+	DatabaseHost     string `mapstructure:"database_host" validate:"required"`
+	DatabasePort     string `mapstructure:"database_port" validate:"port"`
+	DatabaseDriver   string `mapstructure:"database_driver" validate:"oneof=postgres mysql"`
+	DatabaseCharset  string `mapstructure:"database_charset"`
+	DatabaseUsername string `mapstructure:"database_username"`
+	DatabasePassword string `mapstructure:"database_password"`
+	// Deprecated: MySQL connection always enables parseTime, DATETIME columns are scanned
+	// into time.Time. False is ignored with a warning.
+	DatabaseParsetime bool `mapstructure:"database_parsetime" default:"true"`
 
-	DatabaseMaxOpenConnection     int           `mapstructure:"database_max_open_connection"`
-	DatabaseMaxIdleConnection     int           `mapstructure:"database_max_idle_connection"`
+	// DatabaseAutoMigrate applies pending migrations on boot
+	DatabaseAutoMigrate bool `mapstructure:"database_auto_migrate"`
+
+	DatabaseMaxOpenConnection     int           `mapstructure:"database_max_open_connection" validate:"min=0"`
+	DatabaseMaxIdleConnection     int           `mapstructure:"database_max_idle_connection" validate:"min=0"`
 	DatabaseMaxIdleTimeConnection time.Duration `mapstructure:"database_max_idle_time_connection"` // in seconds or minutes as needed
 	DatabaseMaxLifetimeConnection time.Duration `mapstructure:"database_max_lifetime_connection"`  // in seconds or minutes as needed
 }
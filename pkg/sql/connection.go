@@ -2,6 +2,7 @@ package sql
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -23,14 +24,19 @@ func NewClient(cfg *SQLConfig) (*sqlx.DB, error) {
 }
 
 func (s *sqlClient) newConn() (*sqlx.DB, error) {
-	dsn := s.constructDSN()
-	if s.cfg.DatabaseDriver == "postgres" {
-		s.cfg.DatabaseDriver = "pgx"
+	dsn, err := s.constructDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	driverName := s.cfg.DatabaseDriver
+	if driverName == "postgres" {
+		driverName = "pgx"
 	}
 
-	db, err := sqlx.Open(s.cfg.DatabaseDriver, dsn)
+	db, err := sqlx.Open(driverName, dsn)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	if err := db.Ping(); err != nil {
@@ -60,11 +66,15 @@ func (s *sqlClient) newConn() (*sqlx.DB, error) {
 	return db, nil
 }
 
+// newMySQLDataSourceName always enables parseTime, so DATETIME columns are scanned into time.Time
 func (s *sqlClient) newMySQLDataSourceName() string {
+	if !s.cfg.DatabaseParsetime {
+		slog.Warn("[SQL] database_parsetime is deprecated, false is ignored and parseTime is enabled")
+	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=%v",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=%s&parseTime=true",
 		s.cfg.DatabaseUsername, s.cfg.DatabasePassword, s.cfg.DatabaseHost, s.cfg.DatabasePort,
-		s.cfg.DatabaseName, s.cfg.DatabaseCharset, s.cfg.DatabaseParsetime,
+		s.cfg.DatabaseName, s.cfg.DatabaseCharset,
 	)
 
 	return dsn
@@ -80,13 +90,13 @@ func (s *sqlClient) newPostgresDataSourceName() string {
 	return dsn
 }
 
-func (s *sqlClient) constructDSN() string {
+func (s *sqlClient) constructDSN() (string, error) {
 	switch strings.ToLower(s.cfg.DatabaseDriver) {
 	case "postgres":
-		return s.newPostgresDataSourceName()
+		return s.newPostgresDataSourceName(), nil
 	case "mysql":
-		return s.newMySQLDataSourceName()
+		return s.newMySQLDataSourceName(), nil
 	default:
-		panic("please specify your own db driver")
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedDialect, s.cfg.DatabaseDriver)
 	}
 }
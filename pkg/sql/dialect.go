@@ -0,0 +1,166 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Name of the supported dialects, it is also the directory name of dialect migration set
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+)
+
+// ErrUnsupportedDialect is returned for database driver without dialect
+var ErrUnsupportedDialect = errors.New("unsupported sql dialect")
+
+// IDialect hides SQL syntax difference between the supported databases. Queries are
+// written with ? bindvar and rebound to the dialect placeholder.
+type IDialect interface {
+	Name() string
+
+	// Rebind replaces ? bindvar with the dialect placeholder
+	Rebind(query string) string
+
+	// Upsert returns named insert query of columns that updates updateColumns when
+	// conflictColumns already exist. MySQL resolves the conflict on any unique key.
+	Upsert(table string, columns, conflictColumns, updateColumns []string) string
+
+	// InsertID executes insert query written with ? bindvar and returns generated id of the row
+	InsertID(ctx context.Context, db sqlx.ExtContext, query string, args ...any) (int64, error)
+
+	// TimestampType is the column type of timestamp with microsecond precision, it is
+	// used by table created in code (e.g. migration table)
+	TimestampType() string
+
+	// Timestamp returns t as it is stored by timestamp column, so the value written
+	// equals the value read back
+	Timestamp(t time.Time) time.Time
+
+	// Lock takes session advisory lock on conn, the returned func releases it
+	Lock(ctx context.Context, conn *sqlx.Conn, name string) (func(), error)
+}
+
+// DialectOf returns dialect of sqlx driver name
+func DialectOf(driverName string) (IDialect, error) {
+	switch driverName {
+	case "pgx", "postgres":
+		return postgresDialect{}, nil
+	case "mysql":
+		return mysqlDialect{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, driverName)
+	}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return DialectPostgres
+}
+
+func (postgresDialect) Rebind(query string) string {
+	return sqlx.Rebind(sqlx.DOLLAR, query)
+}
+
+func (postgresDialect) Upsert(table string, columns, conflictColumns, updateColumns []string) string {
+	updates := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		updates[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s",
+		namedInsert(table, columns), strings.Join(conflictColumns, ", "), strings.Join(updates, ", "))
+}
+
+// InsertID uses RETURNING clause, pgx does not support LastInsertId
+func (d postgresDialect) InsertID(ctx context.Context, db sqlx.ExtContext, query string, args ...any) (int64, error) {
+	var id int64
+	err := db.QueryRowxContext(ctx, d.Rebind(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgresDialect) TimestampType() string {
+	return "TIMESTAMPTZ"
+}
+
+func (postgresDialect) Timestamp(t time.Time) time.Time {
+	return utcMicroseconds(t)
+}
+
+func (postgresDialect) Lock(ctx context.Context, conn *sqlx.Conn, name string) (func(), error) {
+	// Unlock must run even when ctx is cancelled
+	unlockCtx := context.WithoutCancel(ctx)
+	key := int64(crc32.ChecksumIEEE([]byte(name)))
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		return nil, err
+	}
+
+	return func() { conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock($1)", key) }, nil
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return DialectMySQL
+}
+
+func (mysqlDialect) Rebind(query string) string {
+	return query
+}
+
+func (mysqlDialect) Upsert(table string, columns, conflictColumns, updateColumns []string) string {
+	updates := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		updates[i] = fmt.Sprintf("%s = VALUES(%s)", column, column)
+	}
+
+	return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", namedInsert(table, columns), strings.Join(updates, ", "))
+}
+
+func (mysqlDialect) InsertID(ctx context.Context, db sqlx.ExtContext, query string, args ...any) (int64, error) {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// TimestampType is DATETIME, TIMESTAMP of MySQL overflows in 2038
+func (mysqlDialect) TimestampType() string {
+	return "DATETIME(6)"
+}
+
+// Timestamp converts t to UTC, DATETIME has no time zone and the driver reads it as UTC
+func (mysqlDialect) Timestamp(t time.Time) time.Time {
+	return utcMicroseconds(t)
+}
+
+func (mysqlDialect) Lock(ctx context.Context, conn *sqlx.Conn, name string) (func(), error) {
+	unlockCtx := context.WithoutCancel(ctx)
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", name).Scan(&acquired); err != nil {
+		return nil, err
+	}
+	if acquired.Int64 != 1 {
+		return nil, fmt.Errorf("lock %s is not acquired", name)
+	}
+
+	return func() { conn.ExecContext(unlockCtx, "SELECT RELEASE_LOCK(?)", name) }, nil
+}
+
+func namedInsert(table string, columns []string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (:%s)", table, strings.Join(columns, ", "), strings.Join(columns, ", :"))
+}
+
+// utcMicroseconds truncates t to microseconds, the precision of TIMESTAMPTZ and DATETIME(6)
+func utcMicroseconds(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,100 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+func TestDialect(t *testing.T) {
+	cases := []struct {
+		driver        string
+		name          string
+		rebind        string
+		upsert        string
+		timestampType string
+		// expectInsert expects insert query and its generated id 7
+		expectInsert func(mock sqlmock.Sqlmock)
+	}{
+		{
+			driver:        "pgx",
+			name:          DialectPostgres,
+			rebind:        "SELECT * FROM t WHERE a = $1 AND b = $2",
+			upsert:        "INSERT INTO t (k, v) VALUES (:k, :v) ON CONFLICT (k) DO UPDATE SET v = EXCLUDED.v",
+			timestampType: "TIMESTAMPTZ",
+			expectInsert: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO t (k) VALUES ($1) RETURNING id")).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+			},
+		},
+		{
+			driver:        "mysql",
+			name:          DialectMySQL,
+			rebind:        "SELECT * FROM t WHERE a = ? AND b = ?",
+			upsert:        "INSERT INTO t (k, v) VALUES (:k, :v) ON DUPLICATE KEY UPDATE v = VALUES(v)",
+			timestampType: "DATETIME(6)",
+			expectInsert: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO t (k) VALUES (?)")).
+					WithArgs("a").
+					WillReturnResult(sqlmock.NewResult(7, 1))
+			},
+		},
+	}
+
+	local := time.FixedZone("UTC+7", 7*60*60)
+	at := time.Date(2025, 1, 2, 10, 4, 5, 123456789, local)
+
+	for _, tc := range cases {
+		t.Run(tc.driver, func(t *testing.T) {
+			dialect, err := DialectOf(tc.driver)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dialect.Name() != tc.name {
+				t.Errorf("Expected name %s, got: %s", tc.name, dialect.Name())
+			}
+			if got := dialect.Rebind("SELECT * FROM t WHERE a = ? AND b = ?"); got != tc.rebind {
+				t.Errorf("Expected rebind %q, got: %q", tc.rebind, got)
+			}
+			if got := dialect.Upsert("t", []string{"k", "v"}, []string{"k"}, []string{"v"}); got != tc.upsert {
+				t.Errorf("Expected upsert %q, got: %q", tc.upsert, got)
+			}
+			if got := dialect.TimestampType(); got != tc.timestampType {
+				t.Errorf("Expected timestamp type %s, got: %s", tc.timestampType, got)
+			}
+
+			timestamp := dialect.Timestamp(at)
+			if timestamp.Location() != time.UTC || timestamp.Nanosecond() != 123456000 || !timestamp.Equal(at.Truncate(time.Microsecond)) {
+				t.Errorf("Expected UTC timestamp in microseconds, got: %v", timestamp)
+			}
+
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			tc.expectInsert(mock)
+			id, err := dialect.InsertID(context.Background(), sqlx.NewDb(mockDB, tc.driver), "INSERT INTO t (k) VALUES (?)", "a")
+			if err != nil || id != 7 {
+				t.Errorf("Expected generated id 7, got: %d, %v", id, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if _, err := DialectOf("sqlite3"); !errors.Is(err, ErrUnsupportedDialect) {
+		t.Errorf("Expected ErrUnsupportedDialect, got: %v", err)
+	}
+}
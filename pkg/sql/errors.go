@@ -0,0 +1,58 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Driver specific error code of unique constraint violation
+const (
+	pgUniqueViolation   = "23505"
+	mysqlDuplicateEntry = 1062
+)
+
+// Driver specific error code of transaction that can succeed when it is retried
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	mysqlLockWaitTimeout   = 1205
+	mysqlDeadlock          = 1213
+)
+
+// IsUniqueViolation reports whether err is unique constraint violation of pgx or MySQL driver
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+
+	return false
+}
+
+// IsNoRows reports whether err is returned because query has no result
+func IsNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// IsRetryable reports whether transaction failed because of serialization failure or deadlock
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package sql_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+func TestDriverErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		uniqueViol bool
+		noRows     bool
+		retryable  bool
+	}{
+		{"pgx unique violation", fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505"}), true, false, false},
+		{"pgx other error", &pgconn.PgError{Code: "23503"}, false, false, false},
+		{"pgx serialization failure", fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40001"}), false, false, true},
+		{"pgx deadlock", &pgconn.PgError{Code: "40P01"}, false, false, true},
+		{"mysql duplicate entry", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062}), true, false, false},
+		{"mysql other error", &mysql.MySQLError{Number: 1452}, false, false, false},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, false, false, true},
+		{"mysql lock wait timeout", &mysql.MySQLError{Number: 1205}, false, false, true},
+		{"no rows", fmt.Errorf("select: %w", sql.ErrNoRows), false, true, false},
+		{"unknown", errors.New("connection refused"), false, false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if IsUniqueViolation(tc.err) != tc.uniqueViol {
+				t.Errorf("Expected IsUniqueViolation %v", tc.uniqueViol)
+			}
+			if IsNoRows(tc.err) != tc.noRows {
+				t.Errorf("Expected IsNoRows %v", tc.noRows)
+			}
+			if IsRetryable(tc.err) != tc.retryable {
+				t.Errorf("Expected IsRetryable %v", tc.retryable)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package sql
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidMigration is returned for migration file that can not be parsed
+	ErrInvalidMigration = errors.New("invalid migration")
+	// ErrChecksumMismatch is returned when applied migration file has been modified
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrIrreversibleMigration is returned when rolling back migration without down section
+	ErrIrreversibleMigration = errors.New("migration has no down section")
+	// ErrMigrationNotFound is returned when target version or applied version has no migration file
+	ErrMigrationNotFound = errors.New("migration not found")
+)
+
+// Annotations recognized in migration file
+const (
+	annotationUp             = "-- +migrate Up"
+	annotationDown           = "-- +migrate Down"
+	annotationStatementBegin = "-- +migrate StatementBegin"
+	annotationStatementEnd   = "-- +migrate StatementEnd"
+)
+
+// Migration is a single versioned file of the migrations directory
+type Migration struct {
+	Version  int64
+	Name     string
+	Checksum string
+	Up       []string
+	Down     []string
+}
+
+// ParseMigrations reads every .sql file of fsys root as migration. File name must start
+// with the version number, e.g. 0001-create_user_table.sql.
+//
+// Statements before "-- +migrate Down" are applied on up, statements after it on down,
+// "-- +migrate Up" is optional. Statements are separated by semicolon at the end of line,
+// statement that contains semicolon inside (e.g. function body) must be wrapped by
+// "-- +migrate StatementBegin" and "-- +migrate StatementEnd".
+func ParseMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	versions := make(map[int64]string)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, err := parseMigration(entry.Name(), string(content))
+		if err != nil {
+			return nil, err
+		}
+
+		if other, ok := versions[migration.Version]; ok {
+			return nil, fmt.Errorf("%w: version %d is used by %s and %s", ErrInvalidMigration, migration.Version, other, entry.Name())
+		}
+		versions[migration.Version] = entry.Name()
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func parseMigration(filename, content string) (Migration, error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	digits := strings.IndexFunc(base, func(r rune) bool { return r < '0' || r > '9' })
+	if digits == -1 {
+		digits = len(base)
+	}
+
+	version, err := strconv.ParseInt(base[:digits], 10, 64)
+	if err != nil || version <= 0 {
+		return Migration{}, fmt.Errorf("%w: %s must start with positive version number", ErrInvalidMigration, filename)
+	}
+
+	up, down, err := splitSections(content)
+	if err != nil {
+		return Migration{}, fmt.Errorf("%w: %s: %w", ErrInvalidMigration, filename, err)
+	}
+
+	upStatements, err := splitStatements(up)
+	if err != nil {
+		return Migration{}, fmt.Errorf("%w: %s: %w", ErrInvalidMigration, filename, err)
+	}
+	if len(upStatements) == 0 {
+		return Migration{}, fmt.Errorf("%w: %s has no up statement", ErrInvalidMigration, filename)
+	}
+
+	downStatements, err := splitStatements(down)
+	if err != nil {
+		return Migration{}, fmt.Errorf("%w: %s: %w", ErrInvalidMigration, filename, err)
+	}
+
+	// Only up section is part of the checksum, so down section can be added to applied migration
+	sum := sha256.Sum256([]byte(strings.Join(upStatements, "\n")))
+
+	return Migration{
+		Version:  version,
+		Name:     strings.TrimLeft(base[digits:], "-_"),
+		Checksum: hex.EncodeToString(sum[:]),
+		Up:       upStatements,
+		Down:     downStatements,
+	}, nil
+}
+
+// splitSections splits content to up and down section
+func splitSections(content string) (string, string, error) {
+	var up, down strings.Builder
+	section := &up
+	seenUp, seenDown := false, false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case annotationUp:
+			if seenUp || seenDown {
+				return "", "", errors.New("up annotation must be the first section")
+			}
+			seenUp = true
+			continue
+		case annotationDown:
+			if seenDown {
+				return "", "", errors.New("duplicate down annotation")
+			}
+			seenDown = true
+			section = &down
+			continue
+		}
+
+		section.WriteString(line)
+		section.WriteByte('\n')
+	}
+
+	return up.String(), down.String(), scanner.Err()
+}
+
+// splitStatements splits section by semicolon at the end of line, comment only statement is dropped
+func splitStatements(section string) ([]string, error) {
+	var (
+		statements  []string
+		current     strings.Builder
+		inStatement bool
+	)
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); !isCommentOnly(statement) {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(section, "\n") {
+		switch strings.TrimSpace(line) {
+		case annotationStatementBegin:
+			if inStatement {
+				return nil, errors.New("nested statement begin annotation")
+			}
+			inStatement = true
+			continue
+		case annotationStatementEnd:
+			if !inStatement {
+				return nil, errors.New("statement end annotation without begin")
+			}
+			inStatement = false
+			flush()
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteByte('\n')
+		if !inStatement && strings.HasSuffix(strings.TrimSpace(line), ";") {
+			flush()
+		}
+	}
+
+	if inStatement {
+		return nil, errors.New("statement begin annotation without end")
+	}
+	flush()
+
+	return statements, nil
+}
+
+func isCommentOnly(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
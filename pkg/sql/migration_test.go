@@ -0,0 +1,220 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/wahyurudiyan/go-boilerplate/migrations"
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+func TestParseMigrations(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    fstest.MapFS
+		expected []Migration
+		err      error
+	}{
+		{
+			name: "up only",
+			files: fstest.MapFS{
+				"0002_add_index.sql":  {Data: []byte("CREATE INDEX a ON t(a);")},
+				"0001-create_t.sql":   {Data: []byte("CREATE TABLE t (a INT);\n-- comment\nCREATE TABLE u (b INT);\n")},
+				"README.md":           {Data: []byte("not a migration")},
+				"nested/0003-nop.sql": {Data: []byte("SELECT 1;")},
+			},
+			expected: []Migration{
+				{Version: 1, Name: "create_t", Up: []string{"CREATE TABLE t (a INT);", "-- comment\nCREATE TABLE u (b INT);"}},
+				{Version: 2, Name: "add_index", Up: []string{"CREATE INDEX a ON t(a);"}},
+			},
+		},
+		{
+			name: "up and down with statement block",
+			files: fstest.MapFS{
+				"10-fn.sql": {Data: []byte("-- +migrate Up\n-- +migrate StatementBegin\nCREATE FUNCTION f() AS $$ BEGIN; END; $$;\n-- +migrate StatementEnd\n-- +migrate Down\nDROP FUNCTION f;\n")},
+			},
+			expected: []Migration{
+				{Version: 10, Name: "fn", Up: []string{"CREATE FUNCTION f() AS $$ BEGIN; END; $$;"}, Down: []string{"DROP FUNCTION f;"}},
+			},
+		},
+		{
+			name:  "missing version",
+			files: fstest.MapFS{"create.sql": {Data: []byte("SELECT 1;")}},
+			err:   ErrInvalidMigration,
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"0001-a.sql": {Data: []byte("SELECT 1;")},
+				"1-b.sql":    {Data: []byte("SELECT 1;")},
+			},
+			err: ErrInvalidMigration,
+		},
+		{
+			name:  "empty up",
+			files: fstest.MapFS{"0001-a.sql": {Data: []byte("-- +migrate Down\nDROP TABLE t;")}},
+			err:   ErrInvalidMigration,
+		},
+		{
+			name:  "unterminated statement block",
+			files: fstest.MapFS{"0001-a.sql": {Data: []byte("-- +migrate StatementBegin\nSELECT 1;")}},
+			err:   ErrInvalidMigration,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := ParseMigrations(tc.files)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got: %v", tc.err, err)
+			}
+			if len(parsed) != len(tc.expected) {
+				t.Fatalf("Expected %d migrations, got: %d", len(tc.expected), len(parsed))
+			}
+
+			for i, expected := range tc.expected {
+				got := parsed[i]
+				if got.Version != expected.Version || got.Name != expected.Name {
+					t.Errorf("Expected migration %d %s, got: %d %s", expected.Version, expected.Name, got.Version, got.Name)
+				}
+				if len(got.Checksum) != 64 {
+					t.Errorf("Expected sha256 checksum, got: %q", got.Checksum)
+				}
+				if !slices.Equal(got.Up, expected.Up) || !slices.Equal(got.Down, expected.Down) {
+					t.Errorf("Expected statements %q / %q, got: %q / %q", expected.Up, expected.Down, got.Up, got.Down)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	var versions [][]int64
+	for _, driver := range []string{"pgx", "mysql"} {
+		dialect, err := DialectOf(driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		migrationSet, err := migrations.ForDialect(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := ParseMigrations(migrationSet)
+		if err != nil {
+			t.Fatalf("Expected %s migrations to parse, got: %v", dialect.Name(), err)
+		}
+		if len(parsed) == 0 || len(parsed[0].Down) == 0 {
+			t.Errorf("Expected reversible %s migrations, got: %+v", dialect.Name(), parsed)
+		}
+
+		var dialectVersions []int64
+		for _, migration := range parsed {
+			dialectVersions = append(dialectVersions, migration.Version)
+		}
+		versions = append(versions, dialectVersions)
+	}
+
+	if !slices.Equal(versions[0], versions[1]) {
+		t.Errorf("Expected every dialect to have the same versions, got: %v", versions)
+	}
+}
+
+func TestMigratorUp(t *testing.T) {
+	files := fstest.MapFS{
+		"0001-create_t.sql": {Data: []byte("CREATE TABLE t (a INT);\n-- +migrate Down\nDROP TABLE t;")},
+		"0002-create_u.sql": {Data: []byte("CREATE TABLE u (a INT);")},
+	}
+	parsed, err := ParseMigrations(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		checksum string
+		err      error
+	}{
+		{"applies pending migration", parsed[0].Checksum, nil},
+		{"rejects modified migration", "modified", ErrChecksumMismatch},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"}).
+					AddRow(1, "create_t", tc.checksum, time.Now()))
+			if tc.err == nil {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE u (a INT);")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)")).
+					WithArgs(2, "create_u", parsed[1].Checksum).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+			mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			migrator, err := NewMigrator(sqlx.NewDb(mockDB, "pgx"), files)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := migrator.Up(context.Background()); !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got: %v", tc.err, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Expected every query to be executed: %v", err)
+			}
+		})
+	}
+}
+
+func TestMigratorDownIrreversible(t *testing.T) {
+	files := fstest.MapFS{"0001-create_t.sql": {Data: []byte("CREATE TABLE t (a INT);")}}
+	parsed, err := ParseMigrations(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, -1)")).WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"}).
+			AddRow(1, "create_t", parsed[0].Checksum, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	migrator, err := NewMigrator(sqlx.NewDb(mockDB, "mysql"), files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrator.Down(context.Background()); !errors.Is(err, ErrIrreversibleMigration) {
+		t.Fatalf("Expected irreversible migration error, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expected every query to be executed: %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MigrationTable keeps version and checksum of applied migrations
+const MigrationTable = "schema_migrations"
+
+// MigrationStatus is the state of a migration in the database
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+
+	// Modified is true when the file changed after it was applied
+	Modified bool
+	// Missing is true when the migration was applied but the file no longer exists,
+	// e.g. it was applied by newer version of the service
+	Missing bool
+}
+
+type appliedMigration struct {
+	Version   int64     `db:"version"`
+	Name      string    `db:"name"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// Migrator applies migrations of a directory to the database. Every operation holds
+// database advisory lock, so replicas starting at the same time apply migration once.
+type Migrator struct {
+	db         *sqlx.DB
+	dialect    IDialect
+	migrations []Migration
+}
+
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	dialect, err := DialectOf(db.DriverName())
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := ParseMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down rolls back the latest applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return nil
+		}
+
+		latest := applied[len(applied)-1]
+		migration, ok := m.find(latest.Version)
+		if !ok {
+			return fmt.Errorf("%w: applied version %d", ErrMigrationNotFound, latest.Version)
+		}
+		return m.rollback(ctx, conn, migration)
+	})
+}
+
+// To applies or rolls back migrations until version is the latest applied migration,
+// version 0 rolls back every migration
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("%w: version %d", ErrMigrationNotFound, version)
+	}
+
+	return m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		appliedVersions := make(map[int64]bool, len(applied))
+		for _, record := range applied {
+			appliedVersions[record.Version] = true
+			if err := m.verify(record); err != nil {
+				return err
+			}
+		}
+
+		// Roll back newer migrations from the latest one
+		for i := len(applied) - 1; i >= 0 && applied[i].Version > version; i-- {
+			migration, ok := m.find(applied[i].Version)
+			if !ok {
+				return fmt.Errorf("%w: applied version %d", ErrMigrationNotFound, applied[i].Version)
+			}
+			if err := m.rollback(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if appliedVersions[migration.Version] {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status returns every migration file and applied migration ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		records := make(map[int64]appliedMigration, len(applied))
+		for _, record := range applied {
+			records[record.Version] = record
+			if _, ok := m.find(record.Version); !ok {
+				statuses = append(statuses, MigrationStatus{
+					Version:   record.Version,
+					Name:      record.Name,
+					Applied:   true,
+					AppliedAt: record.AppliedAt,
+					Missing:   true,
+				})
+			}
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if record, ok := records[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = record.AppliedAt
+				status.Modified = record.Checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// verify ensures applied migration file has not been modified, applied migration without
+// file is allowed so older replica can still start during rolling deployment
+func (m *Migrator) verify(record appliedMigration) error {
+	migration, ok := m.find(record.Version)
+	if ok && migration.Checksum != record.Checksum {
+		return fmt.Errorf("%w: version %d", ErrChecksumMismatch, record.Version)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) ([]appliedMigration, error) {
+	var applied []appliedMigration
+	query := fmt.Sprintf("SELECT version, name, checksum, applied_at FROM %s ORDER BY version", MigrationTable)
+	if err := conn.SelectContext(ctx, &applied, query); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	insert := m.dialect.Rebind(fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES (?, ?, ?)", MigrationTable))
+	err := inTx(ctx, conn, migration.Up, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, insert, migration.Version, migration.Name, migration.Checksum)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("apply migration %d: %w", migration.Version, err)
+	}
+
+	slog.InfoContext(ctx, "[Migration] applied", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	if len(migration.Down) == 0 {
+		return fmt.Errorf("%w: version %d", ErrIrreversibleMigration, migration.Version)
+	}
+
+	remove := m.dialect.Rebind(fmt.Sprintf("DELETE FROM %s WHERE version = ?", MigrationTable))
+	err := inTx(ctx, conn, migration.Down, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, remove, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("rollback migration %d: %w", migration.Version, err)
+	}
+
+	slog.InfoContext(ctx, "[Migration] rolled back", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+// inTx executes statements and record in single transaction. MySQL commits DDL implicitly,
+// so failed migration there must be fixed manually.
+func inTx(ctx context.Context, conn *sqlx.Conn, statements []string, record func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock, the
+// lock is bound to the session so it is released when the connection is closed.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.dialect.Lock(ctx, conn, MigrationTable)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer unlock()
+
+	createTable := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    applied_at %s NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+)`, MigrationTable, m.dialect.TimestampType())
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
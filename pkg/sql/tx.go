@@ -0,0 +1,201 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Default retry of transaction that fails because of serialization failure or deadlock
+const (
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 20 * time.Millisecond
+)
+
+type txKey struct{}
+
+// txState is the transaction stored in context, depth is the number of savepoint.
+// Each savepoint collects its own afterCommit and hands it to the parent on release,
+// so hook of a rolled back savepoint is dropped.
+type txState struct {
+	db          *sqlx.DB
+	tx          *sqlx.Tx
+	depth       int
+	afterCommit *[]func()
+}
+
+// IExecutor is implemented by both *sqlx.DB and *sqlx.Tx, repository runs its queries
+// on executor so it joins the transaction of the context transparently
+type IExecutor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+}
+
+// Executor returns the transaction of ctx when it is started on db, otherwise db itself
+func Executor(ctx context.Context, db *sqlx.DB) IExecutor {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok && state.db == db {
+		return state.tx
+	}
+	return db
+}
+
+// InTx reports whether ctx carries a transaction started by TxManager
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction of ctx is committed, e.g. to invalidate cache
+// only after the change is visible to other reader. fn runs immediately when ctx has no
+// transaction and never runs when the transaction is rolled back.
+func AfterCommit(ctx context.Context, fn func()) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		fn()
+		return
+	}
+	*state.afterCommit = append(*state.afterCommit, fn)
+}
+
+// ITxManager runs fn in a unit of work, repositories called with ctx given to fn share
+// the same transaction
+type ITxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
+}
+
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+
+	// MaxRetries of transaction that fails because of serialization failure or deadlock,
+	// zero uses the default and negative disables retry
+	MaxRetries int
+}
+
+var _ ITxManager = (*TxManager)(nil)
+
+type TxManager struct {
+	db           *sqlx.DB
+	opts         TxOptions
+	retryBackoff time.Duration
+}
+
+// NewTxManager returns transaction manager of db, opts is used by WithinTx
+func NewTxManager(db *sqlx.DB, opts TxOptions) *TxManager {
+	return &TxManager{
+		db:           db,
+		opts:         opts,
+		retryBackoff: defaultTxRetryBackoff,
+	}
+}
+
+// WithinTx runs fn in transaction with the default options
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithinTxOptions(ctx, m.opts, fn)
+}
+
+// WithinTxOptions commits the transaction when fn returns nil and rolls it back otherwise.
+// When ctx already carries transaction of the same db, fn runs inside a savepoint of it
+// and opts is ignored, so only the outermost transaction is retried.
+func (m *TxManager) WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok && state.db == m.db {
+		return withinSavepoint(ctx, state, fn)
+	}
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultTxMaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.runTx(ctx, opts, fn)
+		if err == nil || !IsRetryable(err) || attempt >= maxRetries {
+			return err
+		}
+
+		slog.WarnContext(ctx, "[SQL] retrying transaction", "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(m.retryBackoff * time.Duration(attempt+1)):
+		}
+	}
+}
+
+func (m *TxManager) runTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) (err error) {
+	tx, err := m.db.BeginTxx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	var afterCommit []func()
+	if err := fn(context.WithValue(ctx, txKey{}, &txState{db: m.db, tx: tx, afterCommit: &afterCommit})); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, hook := range afterCommit {
+		hook()
+	}
+	return nil
+}
+
+// withinSavepoint rolls back only the changes of fn when it fails, together with the
+// hooks registered by fn
+func withinSavepoint(ctx context.Context, state *txState, fn func(ctx context.Context) error) (err error) {
+	var afterCommit []func()
+	nested := &txState{db: state.db, tx: state.tx, depth: state.depth + 1, afterCommit: &afterCommit}
+	savepoint := fmt.Sprintf("sp_%d", nested.depth)
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, nested)); err != nil {
+		state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+
+	*state.afterCommit = append(*state.afterCommit, afterCommit...)
+	return nil
+}
+
+// NopTxManager runs fn without transaction, it is used with store that does not support
+// transaction (e.g. MongoDB repository)
+type NopTxManager struct{}
+
+func (NopTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (NopTxManager) WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,192 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/sql"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mockDB.Close() })
+
+	return sqlx.NewDb(mockDB, "pgx"), mock
+}
+
+func TestWithinTx(t *testing.T) {
+	errBusiness := errors.New("business rule failed")
+	serializationFailure := &pgconn.PgError{Code: "40001"}
+
+	cases := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		// results returned by fn on every attempt
+		results []error
+		err     error
+	}{
+		{
+			name: "commit",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO t").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			results: []error{nil},
+		},
+		{
+			name: "rollback on error",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO t").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+			},
+			results: []error{errBusiness},
+			err:     errBusiness,
+		},
+		{
+			name: "retry serialization failure",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO t").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO t").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			results: []error{serializationFailure, nil},
+		},
+		{
+			name: "retry serialization failure on commit",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO t").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit().WillReturnError(serializationFailure)
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO t").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			results: []error{nil, nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			tc.expect(mock)
+
+			attempt, committed := 0, 0
+			err := NewTxManager(db, TxOptions{}).WithinTx(context.Background(), func(ctx context.Context) error {
+				if _, err := Executor(ctx, db).ExecContext(ctx, "INSERT INTO t VALUES (1)"); err != nil {
+					return err
+				}
+				AfterCommit(ctx, func() { committed++ })
+				attempt++
+				return tc.results[attempt-1]
+			})
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected error %v, got: %v", tc.err, err)
+			}
+			if attempt != len(tc.results) {
+				t.Errorf("Expected %d attempts, got: %d", len(tc.results), attempt)
+			}
+			if expected := map[bool]int{true: 1, false: 0}[tc.err == nil]; committed != expected {
+				t.Errorf("Expected after commit hook to run %d times, got: %d", expected, committed)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestWithinTxNestedSavepoint(t *testing.T) {
+	db, mock := newMockDB(t)
+	errNested := errors.New("nested failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	txManager := NewTxManager(db, TxOptions{})
+	err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+		if Executor(ctx, db) == IExecutor(db) {
+			t.Errorf("Expected executor to be the transaction")
+		}
+
+		// Failure of nested unit of work does not abort the outer transaction
+		if err := txManager.WithinTx(ctx, func(ctx context.Context) error { return errNested }); !errors.Is(err, errNested) {
+			t.Errorf("Expected nested error, got: %v", err)
+		}
+		return txManager.WithinTx(ctx, func(ctx context.Context) error { return nil })
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithinTxSavepointAfterCommit(t *testing.T) {
+	db, mock := newMockDB(t)
+	errNested := errors.New("nested failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	var ran []string
+	hook := func(ctx context.Context, name string) {
+		AfterCommit(ctx, func() { ran = append(ran, name) })
+	}
+
+	txManager := NewTxManager(db, TxOptions{})
+	err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+		hook(ctx, "outer")
+
+		txManager.WithinTx(ctx, func(ctx context.Context) error {
+			hook(ctx, "rolled back")
+			return errNested
+		})
+
+		return txManager.WithinTx(ctx, func(ctx context.Context) error {
+			hook(ctx, "released")
+			return txManager.WithinTx(ctx, func(ctx context.Context) error {
+				hook(ctx, "released nested")
+				return nil
+			})
+		})
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if got := strings.Join(ran, ","); got != "outer,released,released nested" {
+		t.Errorf("Expected hooks of released savepoints only, got: %s", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,27 @@
+package telemetry
+
+import (
+	"net/url"
+	"os"
+)
+
+// defaultCollectorAddress is the OTLP gRPC exporter default endpoint
+const defaultCollectorAddress = "localhost:4317"
+
+// CollectorAddress returns host:port of the OTLP collector the exporters send to, it
+// follows the same environment variables as the exporters.
+func CollectorAddress() string {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		return defaultCollectorAddress
+	}
+
+	// Endpoint may be given as URL (http://collector:4317) or plain host:port
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return endpoint
+}
@@ -0,0 +1,52 @@
+package validation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wahyurudiyan/go-boilerplate/pkg/errs"
+	. "github.com/wahyurudiyan/go-boilerplate/pkg/validation"
+)
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testRequest struct {
+	Email    string      `json:"email,omitempty" validate:"required,email"`
+	Username string      `json:"username,omitempty" validate:"omitempty,alphanum,min=3"`
+	Nickname *string     `json:"nickname,omitempty" validate:"omitempty,max=4"`
+	Address  testAddress `json:"address"`
+}
+
+func TestStruct(t *testing.T) {
+	valid := testRequest{Email: "jane@example.com", Username: "jane", Address: testAddress{City: "Jakarta"}}
+	if err := Struct(valid); err != nil {
+		t.Fatalf("Expected valid request, got: %v", err)
+	}
+
+	nickname := "janedoe"
+	err := Struct(testRequest{Email: "not-an-email", Username: "j", Nickname: &nickname})
+	if !errors.Is(err, ErrInvalidRequest) || errs.KindOf(err) != errs.KindValidation {
+		t.Fatalf("Expected ErrInvalidRequest, got: %v", err)
+	}
+
+	domainErr, _ := errs.As(err)
+	want := map[string]string{
+		"email":        "email",
+		"username":     "min",
+		"nickname":     "max",
+		"address.city": "required",
+	}
+	if len(domainErr.Violations) != len(want) {
+		t.Fatalf("Expected %d violations, got: %+v", len(want), domainErr.Violations)
+	}
+	for _, violation := range domainErr.Violations {
+		if want[violation.Field] != violation.Rule {
+			t.Errorf("Unexpected violation: %+v", violation)
+		}
+		if violation.Message == "" {
+			t.Errorf("Expected message on violation: %+v", violation)
+		}
+	}
+}